@@ -2,23 +2,79 @@ package actor
 
 import gherkin "github.com/cucumber/gherkin-go"
 
+// Actor is the model of a .actor file. Every location is 1-based: the
+// Location of an actor or goal points at its keyword, and NameLocation at the
+// start of its name.
 type Actor struct {
 	gherkin.Node
-	Tags  []*gherkin.Tag `json:"tags"`
-	Name  string         `json:"name"`
-	Blurb []string       `json:"blurb,omitempty"`
-	Goals []*Goal        `json:"goals,omitempty"`
+	NameLocation *gherkin.Location `json:"nameLocation,omitempty"`
+	Tags         []*gherkin.Tag    `json:"tags"`
+	Language     string            `json:"language,omitempty"`
+	Name         string            `json:"name"`
+	Aliases      []*Alias          `json:"aliases,omitempty"`
+	Blurb        []string          `json:"blurb,omitempty"`
+	DocString    *DocString        `json:"docString,omitempty"`
+	DataTable    *DataTable        `json:"dataTable,omitempty"`
+	Goals        []*Goal           `json:"goals,omitempty"`
+	Comments     []*Comment        `json:"comments,omitempty"`
+
+	// The file the actor was parsed from, so that WriteToFile can tell if
+	// it has changed since
+	source *fileSource
 }
 
 type Goal struct {
 	gherkin.Node
-	Tags []*gherkin.Tag `json:"tags"`
-	Name string         `json:"name"`
+	NameLocation *gherkin.Location `json:"nameLocation,omitempty"`
+	Tags         []*gherkin.Tag    `json:"tags"`
+	Name         string            `json:"name"`
+	DocString    *DocString        `json:"docString,omitempty"`
+	DataTable    *DataTable        `json:"dataTable,omitempty"`
+}
+
+// Alias is another name the actor is known by, listed under the Aliases
+// keyword. Actors are found by their aliases as well as by their name.
+type Alias struct {
+	gherkin.Node
+	Name string `json:"name"`
+}
+
+// DocString is a block of text kept exactly as written between """ (or ```)
+// delimiters, with an optional media type after the opening delimiter.
+type DocString struct {
+	gherkin.Node
+	ContentType string `json:"contentType,omitempty"`
+	Content     string `json:"content"`
+	Delimiter   string `json:"-"`
+}
+
+// Comment is a # comment found in the file, other than the language header.
+// Comments are informational only; Actor.Write doesn't write them.
+type Comment struct {
+	gherkin.Node
+	Text string `json:"text"`
+}
+
+// DataTable is a Gherkin-style table of | delimited rows.
+type DataTable struct {
+	gherkin.Node
+	Rows []*TableRow `json:"rows"`
+}
+
+type TableRow struct {
+	gherkin.Node
+	Cells []*TableCell `json:"cells"`
+}
+
+type TableCell struct {
+	gherkin.Node
+	Value string `json:"value"`
 }
 
 func NewActor() *Actor {
 	actor := Actor{}
 
+	actor.Language = defaultLanguage
 	actor.Tags = make([]*gherkin.Tag, 0)
 	actor.Blurb = make([]string, 0)
 	actor.Goals = make([]*Goal, 0)
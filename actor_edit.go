@@ -0,0 +1,247 @@
+package actor
+
+import (
+	"fmt"
+	"strings"
+
+	gherkin "github.com/cucumber/gherkin-go"
+)
+
+// Goals, aliases and tags added by these helpers have no location until the actor is
+// written and parsed again, and those already there keep theirs.
+
+// validateName checks that an actor or goal name reads back as written, as
+// the parser trims names and ends them at the end of the line
+func validateName(kind, name string) error {
+
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("%s name must not be empty", kind)
+	}
+
+	if strings.ContainsAny(name, "\r\n") {
+		return fmt.Errorf("%s name '%s' must be a single line", kind, name)
+	}
+
+	if strings.TrimSpace(name) != name {
+		return fmt.Errorf("%s name '%s' must not start or end with whitespace", kind, name)
+	}
+
+	return nil
+}
+
+// validateTagName returns the name of a tag, without any leading @
+func validateTagName(name string) (string, error) {
+
+	name = strings.TrimPrefix(name, "@")
+
+	if !tagMatcher.MatchString("@" + name) {
+		return "", fmt.Errorf("Tag '%s' is not a valid tag name", name)
+	}
+
+	return name, nil
+}
+
+// FindGoal returns the named goal, ignoring case and differences in
+// whitespace, or nil
+func (a *Actor) FindGoal(name string) *Goal {
+
+	name = normaliseName(name)
+
+	for _, goal := range a.Goals {
+		if normaliseName(goal.Name) == name {
+			return goal
+		}
+	}
+
+	return nil
+}
+
+// AddGoal adds a goal, with any tags, after the existing goals
+func (a *Actor) AddGoal(name string, tags ...string) (*Goal, error) {
+
+	if err := validateName("Goal", name); err != nil {
+		return nil, err
+	}
+
+	if a.FindGoal(name) != nil {
+		return nil, fmt.Errorf("Goal '%s' already exists", name)
+	}
+
+	goal := &Goal{Name: name, Tags: make([]*gherkin.Tag, 0)}
+
+	for _, tag := range tags {
+		if err := goal.AddTag(tag); err != nil {
+			return nil, err
+		}
+	}
+
+	a.Goals = append(a.Goals, goal)
+
+	return goal, nil
+}
+
+func (a *Actor) RemoveGoal(name string) error {
+
+	goal := a.FindGoal(name)
+
+	if goal == nil {
+		return fmt.Errorf("Goal '%s' not found", name)
+	}
+
+	for i, g := range a.Goals {
+		if g == goal {
+			a.Goals = append(a.Goals[:i], a.Goals[i+1:]...)
+			break
+		}
+	}
+
+	return nil
+}
+
+func (a *Actor) RenameGoal(from, to string) error {
+
+	goal := a.FindGoal(from)
+
+	if goal == nil {
+		return fmt.Errorf("Goal '%s' not found", from)
+	}
+
+	if err := validateName("Goal", to); err != nil {
+		return err
+	}
+
+	if other := a.FindGoal(to); other != nil && other != goal {
+		return fmt.Errorf("Goal '%s' already exists", to)
+	}
+
+	goal.Name = to
+
+	return nil
+}
+
+// Names returns the actor's name followed by its aliases
+func (a *Actor) Names() []string {
+
+	names := []string{a.Name}
+
+	for _, alias := range a.Aliases {
+		names = append(names, alias.Name)
+	}
+
+	return names
+}
+
+// IsNamed reports whether a name is the actor's name or one of its aliases,
+// ignoring case and differences in whitespace
+func (a *Actor) IsNamed(name string) bool {
+
+	name = normaliseName(name)
+
+	for _, other := range a.Names() {
+		if normaliseName(other) == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AddAlias adds another name for the actor, after its existing aliases
+func (a *Actor) AddAlias(name string) error {
+
+	if err := validateName("Alias", name); err != nil {
+		return err
+	}
+
+	if a.IsNamed(name) {
+		return fmt.Errorf("Alias '%s' is already a name of the actor", name)
+	}
+
+	a.Aliases = append(a.Aliases, &Alias{Name: name})
+
+	return nil
+}
+
+// RemoveAlias removes an alias, and reports whether the actor had it
+func (a *Actor) RemoveAlias(name string) bool {
+
+	name = normaliseName(name)
+
+	for i, alias := range a.Aliases {
+		if normaliseName(alias.Name) == name {
+			a.Aliases = append(a.Aliases[:i], a.Aliases[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// AddTag adds a tag, with or without its @, unless the actor already has it
+func (a *Actor) AddTag(name string) error {
+	return addTag(&a.Tags, name)
+}
+
+// RemoveTag removes a tag, and reports whether the actor had it
+func (a *Actor) RemoveTag(name string) bool {
+	return removeTag(&a.Tags, name)
+}
+
+func (a *Actor) HasTag(name string) bool {
+	return findTag(a.Tags, name) >= 0
+}
+
+// AddTag adds a tag, with or without its @, unless the goal already has it
+func (g *Goal) AddTag(name string) error {
+	return addTag(&g.Tags, name)
+}
+
+// RemoveTag removes a tag, and reports whether the goal had it
+func (g *Goal) RemoveTag(name string) bool {
+	return removeTag(&g.Tags, name)
+}
+
+func (g *Goal) HasTag(name string) bool {
+	return findTag(g.Tags, name) >= 0
+}
+
+func findTag(tags []*gherkin.Tag, name string) int {
+
+	name = strings.TrimPrefix(name, "@")
+
+	for i, tag := range tags {
+		if tag.Name == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func addTag(tags *[]*gherkin.Tag, name string) error {
+
+	name, err := validateTagName(name)
+
+	if err != nil {
+		return err
+	}
+
+	if findTag(*tags, name) < 0 {
+		*tags = append(*tags, &gherkin.Tag{Name: name})
+	}
+
+	return nil
+}
+
+func removeTag(tags *[]*gherkin.Tag, name string) bool {
+
+	i := findTag(*tags, name)
+
+	if i < 0 {
+		return false
+	}
+
+	*tags = append((*tags)[:i], (*tags)[i+1:]...)
+
+	return true
+}
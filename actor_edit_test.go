@@ -0,0 +1,100 @@
+package actor
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ItEditsTheGoalsOfAnActor(t *testing.T) {
+
+	a := newMockActor()
+
+	goal, err := a.AddGoal("Goal 4", "tag5")
+
+	assert.Nil(t, err)
+	assert.Equal(t, goal, a.Goals[3])
+	assert.Equal(t, goal, a.FindGoal("  goal   4 "))
+
+	_, err = a.AddGoal("goal 1")
+	assert.Equal(t, "Goal 'goal 1' already exists", err.Error())
+
+	_, err = a.AddGoal("")
+	assert.Equal(t, "Goal name must not be empty", err.Error())
+
+	assert.Nil(t, a.RenameGoal("Goal 2", "Second goal"))
+	assert.Nil(t, a.RenameGoal("Goal 3", "goal 3"))
+	assert.Equal(t, "Goal 'Goal 1' already exists", a.RenameGoal("Goal 4", "Goal 1").Error())
+	assert.Equal(t, "Goal 'Goal 2' not found", a.RenameGoal("Goal 2", "Goal 5").Error())
+	assert.Equal(t, "Goal name 'Goal\n5' must be a single line", a.RenameGoal("Goal 4", "Goal\n5").Error())
+
+	assert.Nil(t, a.RemoveGoal("GOAL 1"))
+	assert.Equal(t, "Goal 'Goal 1' not found", a.RemoveGoal("Goal 1").Error())
+
+	names := make([]string, 0)
+
+	for _, g := range a.Goals {
+		names = append(names, g.Name)
+	}
+
+	assert.Equal(t, []string{"Second goal", "goal 3", "Goal 4"}, names)
+}
+
+func Test_ItEditsTheAliasesOfAnActor(t *testing.T) {
+
+	a := newMockActor()
+
+	assert.Nil(t, a.AddAlias("Mock"))
+	assert.Nil(t, a.AddAlias("Fake"))
+	assert.Equal(t, "Alias 'mock' is already a name of the actor", a.AddAlias("mock").Error())
+	assert.Equal(t, "Alias 'MOCK ACTOR' is already a name of the actor", a.AddAlias("MOCK ACTOR").Error())
+	assert.Equal(t, "Alias name ' Padded' must not start or end with whitespace", a.AddAlias(" Padded").Error())
+
+	assert.True(t, a.IsNamed("fake"))
+	assert.True(t, a.RemoveAlias("  MOCK "))
+	assert.False(t, a.RemoveAlias("Mock"))
+	assert.False(t, a.IsNamed("Mock"))
+	assert.Equal(t, []string{"Mock actor", "Fake"}, a.Names())
+}
+
+func Test_ItEditsTheTagsOfAnActorAndItsGoals(t *testing.T) {
+
+	a := newMockActor()
+
+	assert.True(t, a.HasTag("@tag1"))
+	assert.False(t, a.HasTag("tag3"))
+
+	assert.Nil(t, a.AddTag("@new"))
+	assert.Nil(t, a.AddTag("new"))
+	assert.Equal(t, "Tag 'has space' is not a valid tag name", a.AddTag("has space").Error())
+	assert.Equal(t, 3, len(a.Tags))
+
+	assert.True(t, a.RemoveTag("tag1"))
+	assert.False(t, a.RemoveTag("tag1"))
+	assert.Equal(t, []string{"tag2", "new"}, []string{a.Tags[0].Name, a.Tags[1].Name})
+
+	goal := a.FindGoal("Goal 1")
+
+	assert.True(t, goal.HasTag("tag3"))
+	assert.True(t, goal.RemoveTag("@tag3"))
+	assert.Nil(t, goal.AddTag("tag5"))
+	assert.Equal(t, []string{"tag4", "tag5"}, []string{goal.Tags[0].Name, goal.Tags[1].Name})
+}
+
+func Test_EditedActorsKeepTheLocationsTheyHad(t *testing.T) {
+
+	a, err := NewParser(bytes.NewBufferString("@tag1\nActor: Someone\n    Goal: First\n    Goal: Second\n")).Parse()
+
+	assert.Nil(t, err)
+	assert.Nil(t, a.RemoveGoal("First"))
+	assert.Nil(t, a.AddTag("tag2"))
+
+	goal, err := a.AddGoal("Third")
+
+	assert.Nil(t, err)
+	assert.Nil(t, goal.Location)
+	assert.Nil(t, a.Tags[1].Location)
+	assert.Equal(t, 1, a.Tags[0].Location.Line)
+	assert.Equal(t, 4, a.Goals[0].Location.Line)
+}
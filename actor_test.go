@@ -16,6 +16,7 @@ func compareActors(t *testing.T, a1, a2 *Actor) {
 	assert.NotNil(t, a1)
 	assert.NotNil(t, a2)
 	assert.Equal(t, a1.Name, a2.Name)
+	assert.Equal(t, a1.Names(), a2.Names())
 
 	// Check general tags
 	assert.Equal(t, len(a1.Tags), len(a2.Tags))
@@ -26,6 +27,8 @@ func compareActors(t *testing.T, a1, a2 *Actor) {
 
 	// Check blurb
 	assert.Equal(t, a1.Blurb, a2.Blurb)
+	compareDocStrings(t, a1.DocString, a2.DocString)
+	compareDataTables(t, a1.DataTable, a2.DataTable)
 
 	// Check goals
 	assert.Equal(t, len(a1.Goals), len(a2.Goals))
@@ -37,7 +40,63 @@ func compareActors(t *testing.T, a1, a2 *Actor) {
 		for j := 0; j < len(a1.Goals[i].Tags); j++ {
 			assert.Equal(t, a1.Goals[i].Tags[j].Name, a2.Goals[i].Tags[j].Name)
 		}
+
+		compareDocStrings(t, a1.Goals[i].DocString, a2.Goals[i].DocString)
+		compareDataTables(t, a1.Goals[i].DataTable, a2.Goals[i].DataTable)
+	}
+}
+
+func compareDocStrings(t *testing.T, d1, d2 *DocString) {
+
+	if d1 == nil || d2 == nil {
+		assert.True(t, d1 == nil && d2 == nil, "Only one doc string is nil")
+		return
+	}
+
+	assert.Equal(t, d1.ContentType, d2.ContentType)
+	assert.Equal(t, d1.Content, d2.Content)
+}
+
+func compareDataTables(t *testing.T, d1, d2 *DataTable) {
+
+	if d1 == nil || d2 == nil {
+		assert.True(t, d1 == nil && d2 == nil, "Only one data table is nil")
+		return
+	}
+
+	assert.Equal(t, len(d1.Rows), len(d2.Rows))
+
+	for i := 0; i < len(d1.Rows) && i < len(d2.Rows); i++ {
+		assert.Equal(t, tableRowValues(d1.Rows[i]), tableRowValues(d2.Rows[i]))
+	}
+}
+
+func tableRowValues(row *TableRow) []string {
+
+	values := make([]string, 0)
+
+	for _, cell := range row.Cells {
+		values = append(values, cell.Value)
+	}
+
+	return values
+}
+
+func newDataTable(rows ...[]string) *DataTable {
+
+	table := &DataTable{}
+
+	for _, values := range rows {
+		row := &TableRow{}
+
+		for _, value := range values {
+			row.Cells = append(row.Cells, &TableCell{Value: value})
+		}
+
+		table.Rows = append(table.Rows, row)
 	}
+
+	return table
 }
 
 func newMockActor() *Actor {
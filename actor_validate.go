@@ -0,0 +1,163 @@
+package actor
+
+import (
+	"fmt"
+	"strings"
+
+	gherkin "github.com/cucumber/gherkin-go"
+)
+
+// ValidationError lists every way in which an actor couldn't be written and
+// parsed back as it is
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+
+	if len(e.Problems) == 1 {
+		return fmt.Sprintf("Actor is not valid: %s", e.Problems[0])
+	}
+
+	return fmt.Sprintf("Actor is not valid (%d problems): %s", len(e.Problems), strings.Join(e.Problems, "; "))
+}
+
+// Validate checks the actor against the rules the parser applies, returning
+// a *ValidationError with all of the problems found, or nil
+func (a *Actor) Validate() error {
+
+	v := &ValidationError{}
+
+	add := func(prefix string, err error) {
+		if err != nil {
+			v.Problems = append(v.Problems, prefix+err.Error())
+		}
+	}
+
+	if _, err := dialectFor(a.Language); err != nil {
+		add("", err)
+	}
+
+	add("", validateName("Actor", a.Name))
+	validateTags("", a.Tags, add)
+
+	names := map[string]bool{normaliseName(a.Name): true}
+
+	for i, alias := range a.Aliases {
+
+		prefix := fmt.Sprintf("Alias %d: ", i+1)
+
+		if alias == nil {
+			add(prefix, fmt.Errorf("Alias is nil"))
+			continue
+		}
+
+		add(prefix, validateName("Alias", alias.Name))
+
+		if names[normaliseName(alias.Name)] {
+			add(prefix, fmt.Errorf("Alias '%s' is already a name of the actor", alias.Name))
+		}
+
+		names[normaliseName(alias.Name)] = true
+	}
+
+	for i, blurb := range a.Blurb {
+		if strings.TrimSpace(blurb) == "" {
+			add("", fmt.Errorf("Blurb line %d is empty", i+1))
+		} else if strings.ContainsAny(blurb, "\r\n") {
+			add("", fmt.Errorf("Blurb line %d must be a single line", i+1))
+		} else if strings.TrimSpace(blurb) != blurb {
+			add("", fmt.Errorf("Blurb line %d must not start or end with whitespace", i+1))
+		}
+	}
+
+	add("", validateDocString(a.DocString))
+	add("", validateDataTable(a.DataTable))
+
+	for i, goal := range a.Goals {
+
+		prefix := fmt.Sprintf("Goal %d: ", i+1)
+
+		if goal == nil {
+			add(prefix, fmt.Errorf("Goal is nil"))
+			continue
+		}
+
+		add(prefix, validateName("Goal", goal.Name))
+		validateTags(prefix, goal.Tags, add)
+		add(prefix, validateDocString(goal.DocString))
+		add(prefix, validateDataTable(goal.DataTable))
+	}
+
+	if len(v.Problems) > 0 {
+		return v
+	}
+
+	return nil
+}
+
+func validateTags(prefix string, tags []*gherkin.Tag, add func(string, error)) {
+
+	for _, tag := range tags {
+		if tag == nil || !tagMatcher.MatchString("@"+tag.Name) {
+			name := ""
+
+			if tag != nil {
+				name = tag.Name
+			}
+
+			add(prefix, fmt.Errorf("Tag '%s' is not a valid tag name", name))
+		}
+	}
+}
+
+func validateDocString(d *DocString) error {
+
+	if d == nil {
+		return nil
+	}
+
+	if strings.ContainsAny(d.ContentType, " \t\r\n") {
+		return fmt.Errorf("Doc string content type '%s' must be a single word", d.ContentType)
+	}
+
+	for _, delimiter := range docStringDelimiters {
+		if d.Delimiter == delimiter {
+			return nil
+		}
+	}
+
+	if d.Delimiter != "" {
+		return fmt.Errorf("Doc string delimiter '%s' must be one of %s", d.Delimiter, strings.Join(docStringDelimiters, ", "))
+	}
+
+	return nil
+}
+
+func validateDataTable(t *DataTable) error {
+
+	if t == nil {
+		return nil
+	}
+
+	if len(t.Rows) == 0 {
+		return fmt.Errorf("Table has no rows")
+	}
+
+	for _, row := range t.Rows {
+		if len(row.Cells) != len(t.Rows[0].Cells) {
+			return fmt.Errorf("Inconsistent cell count within the table (expected %d, found %d)", len(t.Rows[0].Cells), len(row.Cells))
+		}
+	}
+
+	// The parser trims every cell
+	for _, row := range t.Rows {
+		for _, cell := range row.Cells {
+			if strings.TrimSpace(cell.Value) != cell.Value {
+				return fmt.Errorf("Table cell '%s' must not start or end with whitespace", cell.Value)
+			}
+		}
+	}
+
+	return nil
+}
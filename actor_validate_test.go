@@ -0,0 +1,103 @@
+package actor
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"syscall"
+	"testing"
+
+	gherkin "github.com/cucumber/gherkin-go"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_AValidActorHasNoProblems(t *testing.T) {
+
+	actor := newMockActor()
+	actor.DocString = &DocString{ContentType: "markdown", Content: "* One", Delimiter: "```"}
+	actor.DataTable = newDataTable([]string{"a", "b"}, []string{"c", "d"})
+
+	assert.Nil(t, actor.Validate())
+}
+
+func Test_ItReportsEveryProblemWithAnActor(t *testing.T) {
+
+	actor := newMockActor()
+	actor.Name = ""
+	actor.Language = "xx"
+	actor.Tags = append(actor.Tags, &gherkin.Tag{Name: "@tag"}, nil)
+	actor.Aliases = []*Alias{{Name: "Admin"}, nil, {Name: "admin"}, {Name: "Two\nlines"}}
+	actor.Blurb = []string{"Fine", " ", "Two\nlines", "\tIndented"}
+	actor.DocString = &DocString{ContentType: "text plain", Delimiter: "~~~"}
+	actor.DataTable = newDataTable([]string{"a", "b"}, []string{"c"})
+	actor.Goals[0].Name = "Goal\n1"
+	actor.Goals[1].Tags = []*gherkin.Tag{{Name: "not valid"}}
+	actor.Goals[2].Name = " Goal 3"
+	actor.Goals = append(actor.Goals, nil, &Goal{Name: "Goal 5", DataTable: &DataTable{}}, &Goal{Name: "Goal 6", DataTable: newDataTable([]string{"a", " padded "})})
+
+	err := actor.Validate()
+
+	if assert.IsType(t, &ValidationError{}, err) {
+		assert.Equal(t, []string{
+			"Unsupported language 'xx'",
+			"Actor name must not be empty",
+			"Tag '@tag' is not a valid tag name",
+			"Tag '' is not a valid tag name",
+			"Alias 2: Alias is nil",
+			"Alias 3: Alias 'admin' is already a name of the actor",
+			"Alias 4: Alias name 'Two\nlines' must be a single line",
+			"Blurb line 2 is empty",
+			"Blurb line 3 must be a single line",
+			"Blurb line 4 must not start or end with whitespace",
+			"Doc string content type 'text plain' must be a single word",
+			"Inconsistent cell count within the table (expected 2, found 1)",
+			"Goal 1: Goal name 'Goal\n1' must be a single line",
+			"Goal 2: Tag 'not valid' is not a valid tag name",
+			"Goal 3: Goal name ' Goal 3' must not start or end with whitespace",
+			"Goal 4: Goal is nil",
+			"Goal 5: Table has no rows",
+			"Goal 6: Table cell ' padded ' must not start or end with whitespace",
+		}, err.(*ValidationError).Problems)
+	}
+
+	actor = newMockActor()
+	actor.DocString = &DocString{Delimiter: "~~~"}
+
+	assert.Equal(t, "Actor is not valid: Doc string delimiter '~~~' must be one of \"\"\", ```", actor.Validate().Error())
+
+	actor.Name = ""
+
+	assert.Equal(t, "Actor is not valid (2 problems): Actor name must not be empty; Doc string delimiter '~~~' must be one of \"\"\", ```", actor.Validate().Error())
+}
+
+func Test_ItOnlyValidatesWhenWritingIfAsked(t *testing.T) {
+
+	actor := newMockActor()
+	actor.Goals[0].Name = ""
+	buf := &bytes.Buffer{}
+
+	assert.Nil(t, actor.Write(buf))
+
+	_, err := NewParser(buf).Parse()
+	assert.NotNil(t, err)
+
+	buf.Reset()
+
+	assert.Equal(t, "Actor is not valid: Goal 1: Goal name must not be empty", actor.WriteWithOptions(buf, WriteOptions{Validate: true}).Error())
+	assert.Equal(t, 0, buf.Len())
+
+	f, err := ioutil.TempFile("", "go-actor-test")
+	assert.Nil(t, err)
+	f.Close()
+
+	name := f.Name()
+
+	defer syscall.Unlink(name)
+
+	assert.NotNil(t, actor.WriteToFileWithOptions(name, WriteOptions{Validate: true}))
+
+	info, err := os.Stat(name)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), info.Size())
+}
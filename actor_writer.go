@@ -4,76 +4,183 @@ import (
 	"bytes"
 	"fmt"
 	"io"
-	"io/ioutil"
 )
 
+// WriteOptions changes how an actor is written
+type WriteOptions struct {
+	// Validate checks the actor with Validate first, and writes nothing if
+	// it isn't valid
+	Validate bool
+
+	// BackupSuffix, if set, keeps a copy of the file WriteToFile replaces,
+	// named with the suffix added, such as ".bak"
+	BackupSuffix string
+
+	// Force lets WriteToFile replace the file an actor was parsed from even
+	// if the file has changed since
+	Force bool
+}
+
 func (a *Actor) Write(w io.Writer) error {
+	return a.WriteWithOptions(w, WriteOptions{})
+}
+
+func (a *Actor) WriteWithOptions(w io.Writer, options WriteOptions) error {
+
+	if options.Validate {
+		if err := a.Validate(); err != nil {
+			return err
+		}
+	}
 
 	writer := newWriter(w)
 
-	if err := writer.writeTags(a.Tags); err != nil {
-		return fmt.Errorf("Write initial tags: %s", err)
+	if err := writer.setLanguage(a.Language); err != nil {
+		return err
 	}
 
-	if err := writer.writeKeyword("Actor", a.Name); err != nil {
+	return writer.writeActor(a, nil)
+}
+
+// writeActor writes an actor and, for the result of a merge, the conflicts in
+// place of the parts they're about
+func (w *writer) writeActor(a *Actor, m *MergeResult) error {
+
+	if c := m.conflict(conflictLanguage); c != nil {
+		if err := w.writeConflict(c); err != nil {
+			return fmt.Errorf("Write language conflict: %s", err)
+		}
+	} else if err := w.writeLanguage(); err != nil {
+		return fmt.Errorf("Write language header: %s", err)
+	}
+
+	if c := m.conflict(conflictComments); c != nil {
+		if err := w.writeConflict(c); err != nil {
+			return fmt.Errorf("Write comments conflict: %s", err)
+		}
+	} else if m != nil {
+		// Comments aren't written in place, so a merge keeps them at the top
+		if err := w.writeComments(a.Comments); err != nil {
+			return fmt.Errorf("Write comments: %s", err)
+		}
+	}
+
+	// A merge doesn't add the blank line written for an actor without tags,
+	// which the file it's merged into won't have
+	if len(a.Tags) > 0 || m == nil {
+		if err := w.writeTags(a.Tags); err != nil {
+			return fmt.Errorf("Write initial tags: %s", err)
+		}
+	}
+
+	if c := m.conflict(conflictName); c != nil {
+		if err := w.writeConflict(c); err != nil {
+			return fmt.Errorf("Write actor name conflict: %s", err)
+		}
+	} else if err := w.writeKeyword(w.keyword(token_actorDefinition), a.Name); err != nil {
 		return fmt.Errorf("Write actor keyword: %s", err)
 	}
 
-	writer.indent()
+	w.indent()
+
+	if len(a.Aliases) > 0 {
+
+		if err := w.writeKeyword(w.keyword(token_aliases), ""); err != nil {
+			return fmt.Errorf("Write aliases keyword: %s", err)
+		}
+
+		w.indent()
+
+		for _, alias := range a.Aliases {
+			if err := w.writeBlurb(alias.Name); err != nil {
+				return fmt.Errorf("Write alias: %s", err)
+			}
+		}
+
+		w.unindent()
+	}
+
+	if c := m.conflict(conflictBlurb); c != nil {
+		if err := w.writeConflict(c); err != nil {
+			return fmt.Errorf("Write blurb conflict: %s", err)
+		}
+	} else if err := w.writeBlurbs(a.Blurb); err != nil {
+		return fmt.Errorf("Write blurbs: %s", err)
+	}
+
+	if c := m.conflict(conflictDocString); c != nil {
+		if err := w.writeConflict(c); err != nil {
+			return fmt.Errorf("Write doc string conflict: %s", err)
+		}
+	} else if a.DocString != nil {
+		if err := w.writeDocString(a.DocString); err != nil {
+			return fmt.Errorf("Write doc string: %s", err)
+		}
+	}
 
-	for _, blurb := range a.Blurb {
-		if err := writer.writeBlurb(blurb); err != nil {
-			return fmt.Errorf("Write blurbs: %s", err)
+	if c := m.conflict(conflictDataTable); c != nil {
+		if err := w.writeConflict(c); err != nil {
+			return fmt.Errorf("Write data table conflict: %s", err)
+		}
+	} else if a.DataTable != nil {
+		if err := w.writeTable(a.DataTable); err != nil {
+			return fmt.Errorf("Write data table: %s", err)
 		}
 	}
 
-	if len(a.Blurb) > 0 {
-		if err := writer.newLine(); err != nil {
+	conflicted := m.conflict(conflictBlurb) != nil || m.conflict(conflictDocString) != nil || m.conflict(conflictDataTable) != nil
+
+	if len(a.Aliases) > 0 || len(a.Blurb) > 0 || a.DocString != nil || a.DataTable != nil || conflicted {
+		if err := w.newLine(); err != nil {
 			return fmt.Errorf("New line: %s", err)
 		}
 	}
 
-	goalsWithTags := 0
+	goalsWithKeyword := 0
 
-	// Goals with tags
+	// Goals with tags, a doc string or a table need a Goal keyword of their own
 	for _, goal := range a.Goals {
 
-		if len(goal.Tags) > 0 {
+		if c := m.goalConflict(goal); c != nil {
 
-			if err := writer.writeTags(goal.Tags); err != nil {
-				return fmt.Errorf("Write goal tags: %s", err)
+			if err := w.writeConflict(c); err != nil {
+				return fmt.Errorf("Write goal conflict: %s", err)
 			}
 
-			if err := writer.writeKeyword("Goal", goal.Name); err != nil {
-				return fmt.Errorf("Write goal name: %s", err)
+			goalsWithKeyword++
+
+		} else if goal.needsKeyword() {
+
+			if err := w.writeGoal(goal); err != nil {
+				return err
 			}
 
-			goalsWithTags++
+			goalsWithKeyword++
 		}
 	}
 
-	if goalsWithTags > 0 && (len(a.Goals)-goalsWithTags) > 0 {
+	if (len(a.Goals) - goalsWithKeyword) > 0 {
 
-		if err := writer.newLine(); err != nil {
-			return fmt.Errorf("New line: %s", err)
+		if goalsWithKeyword > 0 {
+			if err := w.newLine(); err != nil {
+				return fmt.Errorf("New line: %s", err)
+			}
 		}
 
-		if err := writer.writeKeyword("Goals", ""); err != nil {
+		if err := w.writeKeyword(w.keyword(token_goals), ""); err != nil {
 			return fmt.Errorf("Write goals tag: %s", err)
 		}
 
-		writer.indent()
+		w.indent()
 
-		// Goals without tags
+		// Everything else is written as a simple list
 		for _, goal := range a.Goals {
 
-			if len(goal.Tags) == 0 {
+			if !goal.needsKeyword() && m.goalConflict(goal) == nil {
 
-				if err := writer.writeBlurb(goal.Name); err != nil {
+				if err := w.writeBlurb(goal.Name); err != nil {
 					return fmt.Errorf("Write goal name: %s", err)
 				}
-
-				goalsWithTags++
 			}
 		}
 	}
@@ -81,13 +188,77 @@ func (a *Actor) Write(w io.Writer) error {
 	return nil
 }
 
+func (w *writer) writeBlurbs(blurb []string) error {
+
+	for _, line := range blurb {
+		if err := w.writeBlurb(line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeGoal writes a goal with its own Goal keyword
+func (w *writer) writeGoal(goal *Goal) error {
+
+	if len(goal.Tags) > 0 {
+		if err := w.writeTags(goal.Tags); err != nil {
+			return fmt.Errorf("Write goal tags: %s", err)
+		}
+	}
+
+	if err := w.writeKeyword(w.keyword(token_goal), goal.Name); err != nil {
+		return fmt.Errorf("Write goal name: %s", err)
+	}
+
+	w.indent()
+	defer w.unindent()
+
+	if goal.DocString != nil {
+		if err := w.writeDocString(goal.DocString); err != nil {
+			return fmt.Errorf("Write goal doc string: %s", err)
+		}
+	}
+
+	if goal.DataTable != nil {
+		if err := w.writeTable(goal.DataTable); err != nil {
+			return fmt.Errorf("Write goal data table: %s", err)
+		}
+	}
+
+	return nil
+}
+
+func (g *Goal) needsKeyword() bool {
+	return len(g.Tags) > 0 || g.DocString != nil || g.DataTable != nil
+}
+
+// WriteToFile replaces the file atomically, keeping its permissions (a new
+// file is made 0644). If the actor was parsed from the same file, and the
+// file has changed since, it returns ErrFileChanged.
 func (a *Actor) WriteToFile(name string) error {
+	return a.WriteToFileWithOptions(name, WriteOptions{})
+}
+
+func (a *Actor) WriteToFileWithOptions(name string, options WriteOptions) error {
 
 	buf := &bytes.Buffer{}
 
-	if err := a.Write(buf); err != nil {
+	if err := a.WriteWithOptions(buf, options); err != nil {
+		return err
+	}
+
+	source, err := writeFile(name, buf.Bytes(), 0644, options.BackupSuffix, a.source, options.Force)
+
+	if err != nil {
 		return err
 	}
 
-	return ioutil.WriteFile(name, buf.Bytes(), 0644)
+	// The actor now matches what's in the file
+	if a.source == nil || a.source.path == source.path {
+		a.source = source
+	}
+
+	return nil
 }
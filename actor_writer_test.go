@@ -30,6 +30,163 @@ Actor: Mock actor
 	assert.Equal(t, expected, writer.String())
 }
 
+func Test_ItWritesKeywordsInTheActorsDialect(t *testing.T) {
+
+	actor := newMockActor()
+	actor.Language = "de"
+	buf := &bytes.Buffer{}
+
+	expected := `# language: de
+@tag1 @tag2
+Akteur: Mock actor
+    Blurb line 1
+    BLurb line 2
+
+    @tag3 @tag4
+    Ziel: Goal 1
+
+    Ziele:
+        Goal 2
+        Goal 3
+`
+
+	assert.Nil(t, actor.Write(buf))
+	assert.Equal(t, expected, buf.String())
+
+	read_actor, err := NewParser(buf).Parse()
+	assert.Nil(t, err)
+	assert.Equal(t, "de", read_actor.Language)
+
+	compareActors(t, read_actor, actor)
+}
+
+func Test_ItWritesAliasesBeforeTheBlurb(t *testing.T) {
+
+	actor := newMockActor()
+	actor.Language = "fr"
+	assert.Nil(t, actor.AddAlias("Mock"))
+	assert.Nil(t, actor.AddAlias("Fake # actor"))
+	buf := &bytes.Buffer{}
+
+	expected := `# language: fr
+@tag1 @tag2
+Acteur: Mock actor
+    Alias:
+        Mock
+        Fake \# actor
+    Blurb line 1
+    BLurb line 2
+
+    @tag3 @tag4
+    Objectif: Goal 1
+
+    Objectifs:
+        Goal 2
+        Goal 3
+`
+
+	assert.Nil(t, actor.Write(buf))
+	assert.Equal(t, expected, buf.String())
+
+	read_actor, err := NewParser(buf).Parse()
+	assert.Nil(t, err)
+
+	compareActors(t, read_actor, actor)
+}
+
+func Test_ItWritesDocStringsAndKeepsThemIntact(t *testing.T) {
+
+	actor := newMockActor()
+	actor.DocString = &DocString{ContentType: "markdown", Content: "* one\n\n    indented\n\"\"\" quoted"}
+	actor.Goals[1].DocString = &DocString{Content: "Goal detail"}
+	buf := &bytes.Buffer{}
+
+	expected := `@tag1 @tag2
+Actor: Mock actor
+    Blurb line 1
+    BLurb line 2
+    """markdown
+    * one
+
+        indented
+    \"\"\" quoted
+    """
+
+    @tag3 @tag4
+    Goal: Goal 1
+    Goal: Goal 2
+        """
+        Goal detail
+        """
+
+    Goals:
+        Goal 3
+`
+
+	assert.Nil(t, actor.Write(buf))
+	assert.Equal(t, expected, buf.String())
+
+	read_actor, err := NewParser(buf).Parse()
+	assert.Nil(t, err)
+
+	compareActors(t, read_actor, actor)
+}
+
+func Test_ItWritesDataTablesAndKeepsThemIntact(t *testing.T) {
+
+	actor := newMockActor()
+	actor.DataTable = newDataTable([]string{"device", "frequency"}, []string{"phone", "several times a day"})
+	actor.Goals[2].DataTable = newDataTable([]string{"a\nb", `c\d`})
+	buf := &bytes.Buffer{}
+
+	expected := `@tag1 @tag2
+Actor: Mock actor
+    Blurb line 1
+    BLurb line 2
+    | device | frequency           |
+    | phone  | several times a day |
+
+    @tag3 @tag4
+    Goal: Goal 1
+    Goal: Goal 3
+        | a\nb | c\\d |
+
+    Goals:
+        Goal 2
+`
+
+	assert.Nil(t, actor.Write(buf))
+	assert.Equal(t, expected, buf.String())
+
+	read_actor, err := NewParser(buf).Parse()
+	assert.Nil(t, err)
+
+	assert.Equal(t, "Goal 3", read_actor.Goals[1].Name)
+	compareDataTables(t, actor.DataTable, read_actor.DataTable)
+	compareDataTables(t, actor.Goals[2].DataTable, read_actor.Goals[1].DataTable)
+}
+
+func Test_ItWritesGoalsWhenNoneHaveTags(t *testing.T) {
+
+	actor := newMockActor()
+	actor.Goals[0].Tags = nil
+	buf := &bytes.Buffer{}
+
+	expected := `@tag1 @tag2
+Actor: Mock actor
+    Blurb line 1
+    BLurb line 2
+
+    Goals:
+        Goal 1
+        Goal 2
+        Goal 3
+`
+
+	assert.Nil(t, actor.Write(buf))
+	assert.Equal(t, expected, buf.String())
+}
+
 func Test_ItCanReadAnActorAfterWriting(t *testing.T) {
 
 	actor := newMockActor()
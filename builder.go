@@ -0,0 +1,138 @@
+package actor
+
+import (
+	"fmt"
+)
+
+// Builder puts together an actor one part at a time:
+//
+//	a, err := actor.NewBuilder("Administrator").
+//		Tag("staff").
+//		Blurb("Manages the site").
+//		Goal("Moderate comments", "moderation").
+//		Goal("Publish articles").
+//		Build()
+//
+// The first invalid part stops the build, and Build returns its error.
+type Builder struct {
+	actor *Actor
+	err   error
+}
+
+func NewBuilder(name string) *Builder {
+
+	b := &Builder{actor: NewActor()}
+
+	if b.err = validateName("Actor", name); b.err == nil {
+		b.actor.Name = name
+	}
+
+	return b
+}
+
+func (b *Builder) Language(language string) *Builder {
+
+	if b.err != nil {
+		return b
+	}
+
+	d, err := dialectFor(language)
+
+	if err != nil {
+		b.err = err
+		return b
+	}
+
+	b.actor.Language = d.language
+
+	return b
+}
+
+func (b *Builder) Tag(names ...string) *Builder {
+
+	for _, name := range names {
+		if b.err == nil {
+			b.err = b.actor.AddTag(name)
+		}
+	}
+
+	return b
+}
+
+func (b *Builder) Alias(names ...string) *Builder {
+
+	for _, name := range names {
+		if b.err == nil {
+			b.err = b.actor.AddAlias(name)
+		}
+	}
+
+	return b
+}
+
+func (b *Builder) Blurb(lines ...string) *Builder {
+
+	if b.err == nil {
+		b.actor.Blurb = append(b.actor.Blurb, lines...)
+	}
+
+	return b
+}
+
+func (b *Builder) DocString(contentType, content string) *Builder {
+
+	if b.err == nil {
+		b.actor.DocString = &DocString{ContentType: contentType, Content: content}
+	}
+
+	return b
+}
+
+// Table sets the actor's data table, which must have the same number of
+// cells in every row
+func (b *Builder) Table(rows ...[]string) *Builder {
+
+	if b.err != nil {
+		return b
+	}
+
+	table := &DataTable{Rows: make([]*TableRow, 0, len(rows))}
+
+	for _, cells := range rows {
+
+		if len(cells) != len(rows[0]) {
+			b.err = fmt.Errorf("Inconsistent cell count within the table (expected %d, found %d)", len(rows[0]), len(cells))
+			return b
+		}
+
+		row := &TableRow{Cells: make([]*TableCell, 0, len(cells))}
+
+		for _, value := range cells {
+			row.Cells = append(row.Cells, &TableCell{Value: value})
+		}
+
+		table.Rows = append(table.Rows, row)
+	}
+
+	b.actor.DataTable = table
+
+	return b
+}
+
+func (b *Builder) Goal(name string, tags ...string) *Builder {
+
+	if b.err == nil {
+		_, b.err = b.actor.AddGoal(name, tags...)
+	}
+
+	return b
+}
+
+func (b *Builder) Build() (*Actor, error) {
+
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	return b.actor, nil
+}
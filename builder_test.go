@@ -0,0 +1,64 @@
+package actor
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ItBuildsAnActorThatReadsBack(t *testing.T) {
+
+	a, err := NewBuilder("Administrator").
+		Language("fr").
+		Tag("staff", "@admin").
+		Alias("Admin", "Site admin").
+		Blurb("Manages the site", "and its users").
+		DocString("markdown", "* Users\n  * Guests").
+		Table([]string{"device", "frequency"}, []string{"laptop", "daily"}).
+		Goal("Moderate comments", "moderation").
+		Goal("Publish articles").
+		Build()
+
+	assert.Nil(t, err)
+	assert.Equal(t, "fr", a.Language)
+	assert.True(t, a.HasTag("admin"))
+	assert.True(t, a.IsNamed("site admin"))
+	assert.True(t, a.FindGoal("moderate comments").HasTag("moderation"))
+
+	buf := &bytes.Buffer{}
+	assert.Nil(t, a.Write(buf))
+
+	read_actor, err := NewParser(buf).Parse()
+
+	assert.Nil(t, err)
+	assert.Equal(t, "fr", read_actor.Language)
+	compareActors(t, a, read_actor)
+}
+
+func Test_ItReportsTheFirstInvalidPartOfABuild(t *testing.T) {
+
+	for _, test := range []struct {
+		builder  *Builder
+		expected string
+	}{
+		{NewBuilder(""), "Actor name must not be empty"},
+		{NewBuilder(" Padded"), "Actor name ' Padded' must not start or end with whitespace"},
+		{NewBuilder("Two\nlines"), "Actor name 'Two\nlines' must be a single line"},
+		{NewBuilder("A").Language("xx").Tag("not valid"), "Unsupported language 'xx'"},
+		{NewBuilder("A").Tag("ok", "not valid"), "Tag 'not valid' is not a valid tag name"},
+		{NewBuilder("A").Table([]string{"a", "b"}, []string{"c"}), "Inconsistent cell count within the table (expected 2, found 1)"},
+		{NewBuilder("A").Goal("G").Goal("g"), "Goal 'g' already exists"},
+		{NewBuilder("A").Alias("B", "a"), "Alias 'a' is already a name of the actor"},
+		{NewBuilder("A").Alias(""), "Alias name must not be empty"},
+		{NewBuilder("A").Goal("G", "@"), "Tag '' is not a valid tag name"},
+	} {
+		a, err := test.builder.Build()
+
+		assert.Nil(t, a)
+
+		if assert.NotNil(t, err) {
+			assert.Equal(t, test.expected, err.Error())
+		}
+	}
+}
@@ -0,0 +1,21 @@
+// Command actor-lsp is a Language Server Protocol server for .actor files,
+// which talks to the editor over stdin and stdout.
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/dryvercorp/actor/lsp"
+)
+
+func main() {
+
+	server := lsp.NewServer(os.Stdin, os.Stdout)
+	server.SetLogger(log.New(os.Stderr, "actor-lsp: ", log.LstdFlags))
+
+	if err := server.Serve(); err != nil {
+		log.Printf("actor-lsp: %s", err)
+		os.Exit(1)
+	}
+}
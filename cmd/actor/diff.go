@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/dryvercorp/actor"
+)
+
+var diffCommand = &command{
+	name:  "diff",
+	short: "list the changes between two versions of an .actor file",
+	usage: "[-format text|json] old-file new-file",
+	run:   runDiff,
+}
+
+func runDiff(c *command, args []string, stdout, stderr io.Writer) int {
+
+	flags := c.flags(stderr)
+	format := flags.String("format", "text", "output format: text or json")
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(stderr, "actor diff: unknown format '%s'\n", *format)
+		return 2
+	}
+
+	files := flags.Args()
+	path := ""
+
+	// Git runs a diff driver's command with the path, and the old and new
+	// file, hash and mode of each version, then for a renamed file the new
+	// path and the rename header, and stops if it fails
+	gitDriver := len(files) == 7 || len(files) == 9
+
+	if gitDriver {
+
+		path = files[0]
+
+		if len(files) == 9 {
+			path = files[7]
+		}
+
+		files = []string{files[1], files[4]}
+	}
+
+	if len(files) != 2 {
+		flags.Usage()
+		return 2
+	}
+
+	a, err := parseVersion(files[0])
+
+	if err != nil {
+		fmt.Fprintf(stderr, "actor diff: %s\n", err)
+		return 2
+	}
+
+	b, err := parseVersion(files[1])
+
+	if err != nil {
+		fmt.Fprintf(stderr, "actor diff: %s\n", err)
+		return 2
+	}
+
+	changes := actor.Diff(a, b)
+
+	if *format == "json" {
+		err = changes.WriteJSON(stdout)
+	} else {
+		if path != "" && len(changes) > 0 {
+			_, err = fmt.Fprintf(stdout, "actor diff %s\n", path)
+		}
+
+		if err == nil {
+			err = changes.WriteText(stdout)
+		}
+	}
+
+	if err != nil {
+		fmt.Fprintf(stderr, "actor diff: %s\n", err)
+		return 2
+	}
+
+	// As with diff, differences are a failure, except to git
+	if len(changes) > 0 && !gitDriver {
+		return 1
+	}
+
+	return 0
+}
+
+// parseVersion parses one side of a diff, which may be empty, or git's
+// /dev/null, when the file was added or removed
+func parseVersion(path string) (*actor.Actor, error) {
+
+	parser, err := actor.NewFileParser(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := parser.Parse()
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err)
+	}
+
+	return a, nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_DiffListsTheChangesBetweenTwoFiles(t *testing.T) {
+
+	dir := writeActorFiles(t, map[string]string{
+		"old.actor":    "Actor: Shopper\n    Goals:\n        Browse\n        Buy\n",
+		"new.actor":    "Actor: Shopper\n    @mobile\n    Goal: Buy\n    Goals:\n        Browse\n        Return\n",
+		"broken.actor": "Actor:\n",
+	})
+
+	before, after := filepath.Join(dir, "old.actor"), filepath.Join(dir, "new.actor")
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	assert.Equal(t, 1, run([]string{"diff", before, after}, stdout, stderr))
+	assert.Equal(t, "Goal 'Buy': Tags changed from none to @mobile\nGoal 'Return' added\n", stdout.String())
+
+	stdout.Reset()
+	assert.Equal(t, 0, run([]string{"diff", before, before}, stdout, stderr))
+	assert.Equal(t, "", stdout.String())
+
+	stdout.Reset()
+	assert.Equal(t, 1, run([]string{"diff", "-format", "json", os.DevNull, before}, stdout, stderr))
+	assert.Contains(t, stdout.String(), `"kind": "actor-added"`)
+
+	// As a git diff driver
+	stdout.Reset()
+	assert.Equal(t, 0, run([]string{"diff", "shopper.actor", before, "abc123", "100644", after, "def456", "100644"}, stdout, stderr))
+	assert.Equal(t, "actor diff shopper.actor\nGoal 'Buy': Tags changed from none to @mobile\nGoal 'Return' added\n", stdout.String())
+
+	// and for a renamed file
+	stdout.Reset()
+	assert.Equal(t, 0, run([]string{"diff", "shopper.actor", before, "abc123", "100644", after, "def456", "100644", "customer.actor", "similarity index 90%\nrename from shopper.actor\nrename to customer.actor\n"}, stdout, stderr))
+	assert.Equal(t, "actor diff customer.actor\nGoal 'Buy': Tags changed from none to @mobile\nGoal 'Return' added\n", stdout.String())
+
+	assert.Equal(t, "", stderr.String())
+
+	assert.Equal(t, 2, run([]string{"diff", before, filepath.Join(dir, "broken.actor")}, stdout, stderr))
+	assert.Equal(t, "actor diff: "+filepath.Join(dir, "broken.actor")+": [Line 0001:01] Actor keyword must be followed by an actor name\n", stderr.String())
+
+	assert.Equal(t, 2, run([]string{"diff", before}, stdout, stderr))
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// actorFiles expands the arguments into .actor files, searching directories
+func actorFiles(args []string) ([]string, error) {
+	return findFiles(args, ".actor")
+}
+
+// featureFiles expands the arguments into .feature files, searching
+// directories
+func featureFiles(args []string) ([]string, error) {
+	return findFiles(args, ".feature")
+}
+
+func findFiles(args []string, ext string) ([]string, error) {
+
+	if len(args) == 0 {
+		args = []string{"."}
+	}
+
+	files := make([]string, 0)
+
+	for _, arg := range args {
+
+		info, err := os.Stat(arg)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !info.IsDir() {
+			files = append(files, arg)
+			continue
+		}
+
+		found := make([]string, 0)
+
+		err = filepath.Walk(arg, func(path string, info os.FileInfo, err error) error {
+
+			if err != nil {
+				return err
+			}
+
+			if !info.IsDir() && filepath.Ext(path) == ext {
+				found = append(found, path)
+			}
+
+			return nil
+		})
+
+		if err != nil {
+			return nil, err
+		}
+
+		sort.Strings(found)
+		files = append(files, found...)
+	}
+
+	return files, nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/dryvercorp/actor"
+	"github.com/dryvercorp/actor/lint"
+	"github.com/dryvercorp/actor/report"
+)
+
+var lintCommand = &command{
+	name:  "lint",
+	short: "check the style of .actor files",
+	usage: "[-config file] [-format text|json|sarif|junit] [file or directory...]",
+	run:   runLint,
+}
+
+func runLint(c *command, args []string, stdout, stderr io.Writer) int {
+
+	flags := c.flags(stderr)
+	configPath := flags.String("config", "", "rule configuration (default "+lint.DefaultConfigFile+", if it exists)")
+	format := flags.String("format", "text", "output format: text, json, sarif or junit")
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	write, ok := reportWriter(*format)
+
+	if !ok {
+		fmt.Fprintf(stderr, "actor lint: unknown format '%s'\n", *format)
+		return 2
+	}
+
+	config := &lint.Config{}
+
+	if *configPath == "" {
+		if _, err := os.Stat(lint.DefaultConfigFile); err == nil {
+			*configPath = lint.DefaultConfigFile
+		}
+	}
+
+	if *configPath != "" {
+		var err error
+
+		if config, err = lint.ReadConfigFile(*configPath); err != nil {
+			fmt.Fprintf(stderr, "actor lint: %s\n", err)
+			return 2
+		}
+	}
+
+	linter, err := lint.New(config)
+
+	if err != nil {
+		fmt.Fprintf(stderr, "actor lint: %s\n", err)
+		return 2
+	}
+
+	project, err := loadProject(flags.Args(), actor.ParserOptions{})
+
+	if err != nil {
+		fmt.Fprintf(stderr, "actor lint: %s\n", err)
+		return 1
+	}
+
+	results, err := linter.LintProject(project)
+
+	if err != nil {
+		fmt.Fprintf(stderr, "actor lint: %s\n", err)
+		return 1
+	}
+
+	r := report.New("actor lint", project)
+	r.AddLint(linter, results)
+
+	if err := write(r, stdout); err != nil {
+		fmt.Fprintf(stderr, "actor lint: %s\n", err)
+		return 1
+	}
+
+	if len(r.Findings) > 0 {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var lintFiles = map[string]string{
+	"admin.actor":  "Actor: admin\n    Manages the site\n",
+	"broken.actor": "Actor: Broken\n    Motto: Be bad\n",
+	"good.actor":   "Actor: Good\n    Is good\n",
+	"lint.json":    `{"rules": {"actor-name-case": {"severity": "error"}}}`,
+}
+
+func Test_LintReportsProblemsAsText(t *testing.T) {
+
+	dir := writeActorFiles(t, lintFiles)
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	assert.Equal(t, 1, run([]string{"lint", "-config", filepath.Join(dir, "lint.json"), dir}, stdout, stderr))
+
+	assert.Equal(t,
+		filepath.Join(dir, "broken.actor")+":2:5: error: Unrecognised keyword 'Motto' (syntax)\n"+
+			filepath.Join(dir, "admin.actor")+":1:8: error: Actor name 'admin' should start with a capital letter (actor-name-case)\n",
+		stdout.String())
+	assert.Equal(t, "", stderr.String())
+
+	stdout.Reset()
+	assert.Equal(t, 0, run([]string{"lint", filepath.Join(dir, "good.actor")}, stdout, stderr))
+	assert.Equal(t, "", stdout.String())
+}
+
+func Test_LintReportsProblemsAsJSON(t *testing.T) {
+
+	dir := writeActorFiles(t, lintFiles)
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	assert.Equal(t, 1, run([]string{"lint", "-format", "json", filepath.Join(dir, "admin.actor")}, stdout, stderr))
+
+	var problems []map[string]interface{}
+	assert.Nil(t, json.Unmarshal(stdout.Bytes(), &problems))
+
+	assert.Equal(t, []map[string]interface{}{
+		{
+			"path":     filepath.Join(dir, "admin.actor"),
+			"rule":     "actor-name-case",
+			"location": map[string]interface{}{"line": 1.0, "column": 8.0},
+			"severity": "warning",
+			"message":  "Actor name 'admin' should start with a capital letter",
+		},
+	}, problems)
+}
+
+func Test_LintReportsProblemsAsSARIF(t *testing.T) {
+
+	dir := writeActorFiles(t, lintFiles)
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	assert.Equal(t, 1, run([]string{"lint", "-format", "sarif", filepath.Join(dir, "admin.actor")}, stdout, stderr))
+
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Results []struct {
+				RuleID string `json:"ruleId"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+
+	assert.Nil(t, json.Unmarshal(stdout.Bytes(), &log))
+	assert.Equal(t, "2.1.0", log.Version)
+	assert.Equal(t, "actor-name-case", log.Runs[0].Results[0].RuleID)
+}
+
+func Test_LintRejectsBadArguments(t *testing.T) {
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	assert.Equal(t, 2, run([]string{"lint", "-format", "xml"}, stdout, stderr))
+	assert.Equal(t, "actor lint: unknown format 'xml'\n", stderr.String())
+}
@@ -0,0 +1,76 @@
+// Command actor works with .actor files from the command line.
+//
+//	actor <command> [arguments]
+//
+// Run actor help for the list of commands.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+type command struct {
+	name  string
+	short string
+	usage string
+	run   func(c *command, args []string, stdout, stderr io.Writer) int
+}
+
+var commands = []*command{
+	validateCommand,
+	lintCommand,
+	narrativeCommand,
+	searchCommand,
+	diffCommand,
+	mergeCommand,
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		usage(stdout)
+		return 0
+	}
+
+	for _, c := range commands {
+		if c.name == args[0] {
+			return c.run(c, args[1:], stdout, stderr)
+		}
+	}
+
+	fmt.Fprintf(stderr, "actor: unknown command '%s'\n", args[0])
+	usage(stderr)
+
+	return 2
+}
+
+func usage(w io.Writer) {
+
+	fmt.Fprintln(w, "Usage: actor <command> [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", c.name, c.short)
+	}
+}
+
+func (c *command) flags(stderr io.Writer) *flag.FlagSet {
+
+	flags := flag.NewFlagSet(c.name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: actor %s %s\n", c.name, c.usage)
+		flags.PrintDefaults()
+	}
+
+	return flags
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/dryvercorp/actor"
+)
+
+var mergeCommand = &command{
+	name:  "merge",
+	short: "merge the changes two sides made to an .actor file",
+	usage: "[-o file] base-file our-file their-file",
+	run:   runMerge,
+}
+
+func runMerge(c *command, args []string, stdout, stderr io.Writer) int {
+
+	flags := c.flags(stderr)
+	output := flags.String("o", "", "file to write the merge to, or - for the standard output (default our-file)")
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	files := flags.Args()
+
+	if len(files) != 3 {
+		flags.Usage()
+		return 2
+	}
+
+	versions := make([]*actor.Actor, 0, len(files))
+
+	for _, file := range files {
+
+		a, err := parseVersion(file)
+
+		if err != nil {
+			fmt.Fprintf(stderr, "actor merge: %s\n", err)
+			return 2
+		}
+
+		versions = append(versions, a)
+	}
+
+	result := actor.Merge(versions[0], versions[1], versions[2])
+
+	// Git runs a merge driver with the base, ours and theirs as temporary
+	// files, and takes ours as the merge
+	var err error
+
+	switch *output {
+	case "-":
+		err = result.Write(stdout)
+	case "":
+		err = result.WriteToFile(files[1])
+	default:
+		err = result.WriteToFile(*output)
+	}
+
+	if err != nil {
+		fmt.Fprintf(stderr, "actor merge: %s\n", err)
+		return 2
+	}
+
+	for _, conflict := range result.Conflicts {
+		fmt.Fprintf(stderr, "actor merge: conflict: %s\n", conflict)
+	}
+
+	if !result.Clean() {
+		return 1
+	}
+
+	return 0
+}
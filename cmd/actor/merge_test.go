@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_MergeMergesTwoSidesOfAFile(t *testing.T) {
+
+	dir := writeActorFiles(t, map[string]string{
+		"base.actor":   "Actor: Shopper\n    Buys things\n    Goals:\n        Browse\n        Buy\n",
+		"ours.actor":   "Actor: Shopper\n    Buys things online\n    @mobile\n    Goal: Buy\n    Goals:\n        Browse\n",
+		"theirs.actor": "Actor: Shopper\n    Buys things\n    Goals:\n        Browse\n        Buy\n        Return\n",
+		"other.actor":  "Actor: Shopper\n    Buys things in store\n    Goals:\n        Browse\n        Buy\n",
+		"broken.actor": "Actor:\n",
+	})
+
+	base, ours, theirs := filepath.Join(dir, "base.actor"), filepath.Join(dir, "ours.actor"), filepath.Join(dir, "theirs.actor")
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	assert.Equal(t, 0, run([]string{"merge", "-o", "-", base, ours, theirs}, stdout, stderr))
+	assert.Equal(t, "Actor: Shopper\n    Buys things online\n\n    @mobile\n    Goal: Buy\n\n    Goals:\n        Browse\n        Return\n", stdout.String())
+	assert.Equal(t, "", stderr.String())
+
+	// As a git merge driver, the merge replaces our file
+	stdout.Reset()
+	assert.Equal(t, 0, run([]string{"merge", base, ours, theirs}, stdout, stderr))
+	assert.Equal(t, "", stdout.String())
+
+	merged, err := ioutil.ReadFile(ours)
+	assert.Nil(t, err)
+	assert.Equal(t, "Actor: Shopper\n    Buys things online\n\n    @mobile\n    Goal: Buy\n\n    Goals:\n        Browse\n        Return\n", string(merged))
+
+	output := filepath.Join(dir, "merged.actor")
+	assert.Equal(t, 1, run([]string{"merge", "-o", output, base, ours, filepath.Join(dir, "other.actor")}, stdout, stderr))
+	assert.Equal(t, "actor merge: conflict: Both sides changed the blurb\n", stderr.String())
+
+	merged, err = ioutil.ReadFile(output)
+	assert.Nil(t, err)
+	assert.Contains(t, string(merged), "<<<<<<< ours\n    Buys things online\n=======\n    Buys things in store\n>>>>>>> theirs\n")
+
+	// Comments are kept, at the top of the file
+	assert.Nil(t, ioutil.WriteFile(theirs, []byte("Actor: Shopper # a person\n    Buys things\n    Goals:\n        Browse\n        Buy\n        Return\n"), 0644))
+
+	stderr.Reset()
+	assert.Equal(t, 0, run([]string{"merge", "-o", output, base, ours, theirs}, stdout, stderr))
+	assert.Equal(t, "", stderr.String())
+
+	merged, err = ioutil.ReadFile(output)
+	assert.Nil(t, err)
+	assert.Equal(t, "# a person\nActor: Shopper\n    Buys things online\n\n    @mobile\n    Goal: Buy\n\n    Goals:\n        Browse\n        Return\n", string(merged))
+
+	stderr.Reset()
+	assert.Equal(t, 2, run([]string{"merge", base, ours, filepath.Join(dir, "broken.actor")}, stdout, stderr))
+	assert.Equal(t, "actor merge: "+filepath.Join(dir, "broken.actor")+": [Line 0001:01] Actor keyword must be followed by an actor name\n", stderr.String())
+
+	assert.Equal(t, 2, run([]string{"merge", base, ours}, stdout, stderr))
+}
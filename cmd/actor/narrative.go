@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/dryvercorp/actor"
+	"github.com/dryvercorp/actor/narrative"
+	"github.com/dryvercorp/actor/report"
+)
+
+var narrativeCommand = &command{
+	name:  "narrative",
+	short: "check that each .feature file's narrative names an actor",
+	usage: "[-actors directory] [-format text|json|sarif|junit] [file or directory...]",
+	run:   runNarrative,
+}
+
+func runNarrative(c *command, args []string, stdout, stderr io.Writer) int {
+
+	flags := c.flags(stderr)
+	actors := flags.String("actors", ".", "directory of the project's .actor files")
+	format := flags.String("format", "text", "output format: text, json, sarif or junit")
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	write, ok := reportWriter(*format)
+
+	if !ok {
+		fmt.Fprintf(stderr, "actor narrative: unknown format '%s'\n", *format)
+		return 2
+	}
+
+	project, err := actor.LoadProject(*actors, actor.ParserOptions{})
+
+	if err != nil {
+		fmt.Fprintf(stderr, "actor narrative: %s\n", err)
+		return 1
+	}
+
+	features, err := featureFiles(flags.Args())
+
+	if err != nil {
+		fmt.Fprintf(stderr, "actor narrative: %s\n", err)
+		return 1
+	}
+
+	r := report.New("actor narrative", project)
+	mismatches := make([]*narrative.Mismatch, 0)
+	unreadable := make([]*report.Finding, 0)
+
+	for _, path := range features {
+
+		mismatch, err := narrative.CheckFeatureFile(project, path)
+
+		if err != nil {
+			unreadable = append(unreadable, &report.Finding{Path: path, Rule: report.NarrativeRule, Severity: actor.SeverityError, Message: err.Error()})
+			continue
+		}
+
+		if mismatch != nil {
+			mismatches = append(mismatches, mismatch)
+		}
+	}
+
+	r.AddNarratives(features, mismatches)
+	r.Findings = append(r.Findings, unreadable...)
+
+	if err := write(r, stdout); err != nil {
+		fmt.Fprintf(stderr, "actor narrative: %s\n", err)
+		return 1
+	}
+
+	if r.Errors() > 0 {
+		return 1
+	}
+
+	return 0
+}
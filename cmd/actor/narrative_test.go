@@ -0,0 +1,38 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NarrativeReportsFeaturesThatDontNameAnActor(t *testing.T) {
+
+	dir := writeActorFiles(t, map[string]string{
+		"actors/admin.actor":      "Actor: Administrator\n",
+		"features/admin.feature":  "Feature: Moderation\n  As an administrator\n",
+		"features/typo.feature":   "Feature: Moderation\n  As an admnistrator\n",
+		"features/other.feature":  "Feature: Other\n  As a visitor\n",
+		"features/none.feature":   "Feature: None\n",
+		"features/ignored.actors": "Not a feature",
+	})
+
+	actors, features := filepath.Join(dir, "actors"), filepath.Join(dir, "features")
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	assert.Equal(t, 1, run([]string{"narrative", "-actors", actors, features}, stdout, stderr))
+
+	assert.Equal(t,
+		filepath.Join(features, "other.feature")+":2:3: error: 'visitor' is not an actor (narrative)\n"+
+			filepath.Join(features, "typo.feature")+":2:3: error: 'admnistrator' is not an actor, did you mean 'Administrator' ("+filepath.Join(actors, "admin.actor")+":1:1)? (narrative)\n",
+		stdout.String())
+	assert.Equal(t, "", stderr.String())
+
+	stdout.Reset()
+	assert.Equal(t, 0, run([]string{"narrative", "-actors", actors, filepath.Join(features, "admin.feature")}, stdout, stderr))
+	assert.Equal(t, "", stdout.String())
+
+	assert.Equal(t, 2, run([]string{"narrative", "-format", "xml"}, stdout, stderr))
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"io"
+
+	"github.com/dryvercorp/actor"
+	"github.com/dryvercorp/actor/report"
+)
+
+var reportWriters = map[string]func(r *report.Report, w io.Writer) error{
+	"text":  (*report.Report).WriteText,
+	"json":  (*report.Report).WriteJSON,
+	"sarif": (*report.Report).WriteSARIF,
+	"junit": (*report.Report).WriteJUnit,
+}
+
+func reportWriter(format string) (func(r *report.Report, w io.Writer) error, bool) {
+	write, ok := reportWriters[format]
+	return write, ok
+}
+
+// loadProject parses the .actor files named by the arguments, or found under
+// them
+func loadProject(args []string, options actor.ParserOptions) (*actor.Project, error) {
+
+	files, err := actorFiles(args)
+
+	if err != nil {
+		return nil, err
+	}
+
+	project := &actor.Project{Files: make([]*actor.ProjectFile, 0, len(files))}
+
+	for _, file := range files {
+		project.Files = append(project.Files, actor.LoadProjectFile(file, options))
+	}
+
+	return project, nil
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dryvercorp/actor"
+	"github.com/dryvercorp/actor/index"
+)
+
+var searchCommand = &command{
+	name:  "search",
+	short: "find actors, blurb and goals by their words or tags",
+	usage: "[-actors directory] [-format text|json] query...",
+	run:   runSearch,
+}
+
+func runSearch(c *command, args []string, stdout, stderr io.Writer) int {
+
+	flags := c.flags(stderr)
+	actors := flags.String("actors", ".", "directory of the project's .actor files")
+	format := flags.String("format", "text", "output format: text or json")
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(stderr, "actor search: unknown format '%s'\n", *format)
+		return 2
+	}
+
+	query := strings.Join(flags.Args(), " ")
+
+	if strings.TrimSpace(query) == "" {
+		flags.Usage()
+		return 2
+	}
+
+	project, err := actor.LoadProject(*actors, actor.ParserOptions{})
+
+	if err != nil {
+		fmt.Fprintf(stderr, "actor search: %s\n", err)
+		return 1
+	}
+
+	// Files that don't parse can't be searched, but the rest still can
+	for _, file := range project.Files {
+		if file.Err != nil {
+			fmt.Fprintf(stderr, "actor search: %s: %s\n", file.Path, file.Err)
+		}
+	}
+
+	results := index.New(project).Search(query)
+
+	if *format == "json" {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(results)
+	} else {
+		for _, r := range results {
+			if _, err = fmt.Fprintln(stdout, r); err != nil {
+				break
+			}
+		}
+	}
+
+	if err != nil {
+		fmt.Fprintf(stderr, "actor search: %s\n", err)
+		return 1
+	}
+
+	// As with grep, finding nothing is a failure
+	if len(results) == 0 {
+		return 1
+	}
+
+	return 0
+}
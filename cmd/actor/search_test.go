@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SearchFindsGoalsByWordAndTag(t *testing.T) {
+
+	dir := writeActorFiles(t, map[string]string{
+		"support.actor": "Actor: Support agent\n    @mobile\n    Goal: Refund an order\n    Goal: Answer tickets\n",
+		"shopper.actor": "Actor: Shopper\n    Goal: Request a refund\n",
+		"broken.actor":  "Actor:\n",
+	})
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	assert.Equal(t, 0, run([]string{"search", "-actors", dir, "refund"}, stdout, stderr))
+
+	assert.Equal(t,
+		filepath.Join(dir, "shopper.actor")+":2:11: Shopper: Request a refund\n"+
+			filepath.Join(dir, "support.actor")+":3:11: Support agent: Refund an order\n",
+		stdout.String())
+	assert.Equal(t, "actor search: "+filepath.Join(dir, "broken.actor")+": [Line 0001:01] Actor keyword must be followed by an actor name\n", stderr.String())
+
+	stdout.Reset()
+	assert.Equal(t, 0, run([]string{"search", "-actors", dir, "-format", "json", "refund", "@mobile"}, stdout, stderr))
+
+	var results []map[string]interface{}
+
+	assert.Nil(t, json.Unmarshal(stdout.Bytes(), &results))
+
+	if assert.Equal(t, 1, len(results)) {
+		assert.Equal(t, "Support agent", results[0]["actor"])
+		assert.Equal(t, "Refund an order", results[0]["goal"])
+		assert.Equal(t, "goal", results[0]["kind"])
+		assert.Equal(t, []interface{}{"mobile"}, results[0]["tags"])
+	}
+
+	stdout.Reset()
+	assert.Equal(t, 1, run([]string{"search", "-actors", dir, "-format", "json", "nothing"}, stdout, stderr))
+	assert.Equal(t, "[]\n", stdout.String())
+
+	assert.Equal(t, 2, run([]string{"search", "-actors", dir}, stdout, stderr))
+	assert.Equal(t, 2, run([]string{"search", "-format", "xml", "refund"}, stdout, stderr))
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/dryvercorp/actor"
+	"github.com/dryvercorp/actor/report"
+)
+
+var validateCommand = &command{
+	name:  "validate",
+	short: "parse .actor files and report any problems",
+	usage: "[-mode default|lenient|strict] [-tab-width n] [-tags file] [-format text|json|sarif|junit] [file or directory...]",
+	run:   runValidate,
+}
+
+var modes = map[string]actor.Mode{
+	"default": actor.ModeDefault,
+	"lenient": actor.ModeLenient,
+	"strict":  actor.ModeStrict,
+}
+
+func runValidate(c *command, args []string, stdout, stderr io.Writer) int {
+
+	flags := c.flags(stderr)
+	modeName := flags.String("mode", "default", "parsing mode: default, lenient or strict")
+	tabWidth := flags.Int("tab-width", 4, "distance between tab stops when measuring indentation")
+	tagsPath := flags.String("tags", "", "tag registry (default "+actor.DefaultTagRegistryFile+", if it exists)")
+	format := flags.String("format", "text", "output format: text, json, sarif or junit")
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	mode, ok := modes[*modeName]
+
+	if !ok {
+		fmt.Fprintf(stderr, "actor validate: unknown mode '%s'\n", *modeName)
+		return 2
+	}
+
+	write, ok := reportWriter(*format)
+
+	if !ok {
+		fmt.Fprintf(stderr, "actor validate: unknown format '%s'\n", *format)
+		return 2
+	}
+
+	options := actor.ParserOptions{Mode: mode, TabWidth: *tabWidth}
+
+	if *tagsPath == "" {
+		if _, err := os.Stat(actor.DefaultTagRegistryFile); err == nil {
+			*tagsPath = actor.DefaultTagRegistryFile
+		}
+	}
+
+	if *tagsPath != "" {
+		var err error
+
+		if options.Tags, err = actor.ReadTagRegistryFile(*tagsPath); err != nil {
+			fmt.Fprintf(stderr, "actor validate: %s\n", err)
+			return 2
+		}
+	}
+
+	project, err := loadProject(flags.Args(), options)
+
+	if err != nil {
+		fmt.Fprintf(stderr, "actor validate: %s\n", err)
+		return 1
+	}
+
+	if *format == "text" {
+		write = writeValidateText
+	}
+
+	if err := write(report.New("actor validate", project), stdout); err != nil {
+		fmt.Fprintf(stderr, "actor validate: %s\n", err)
+		return 1
+	}
+
+	failed := 0
+
+	for _, file := range project.Files {
+		if file.Err != nil {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		fmt.Fprintf(stderr, "%d of %d files failed to parse\n", failed, len(project.Files))
+		return 1
+	}
+
+	return 0
+}
+
+// writeValidateText writes the diagnostics without their rule, which is
+// always the syntax rule
+func writeValidateText(r *report.Report, w io.Writer) error {
+
+	for _, f := range r.Findings {
+
+		var err error
+
+		if f.Location == nil {
+			_, err = fmt.Fprintf(w, "%s: %s: %s\n", f.Path, f.Severity, f.Message)
+		} else {
+			_, err = fmt.Fprintf(w, "%s:%d:%d: %s: %s\n", f.Path, f.Location.Line, f.Location.Column, f.Severity, f.Message)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
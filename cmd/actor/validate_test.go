@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeActorFiles(t *testing.T, files map[string]string) string {
+
+	dir := t.TempDir()
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		assert.Nil(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.Nil(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+
+	return dir
+}
+
+func Test_ValidateReportsDiagnosticsForEachFile(t *testing.T) {
+
+	dir := writeActorFiles(t, map[string]string{
+		"good.actor":       "Actor: Good\n    Goal: Be good\n",
+		"nested/bad.actor": "Actor: Bad\n    Motto: Be bad\n",
+		"ignored.txt":      "Not: an actor",
+	})
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	assert.Equal(t, 1, run([]string{"validate", dir}, stdout, stderr))
+	assert.Equal(t, filepath.Join(dir, "nested/bad.actor")+":2:5: error: Unrecognised keyword 'Motto'\n", stdout.String())
+	assert.Equal(t, "1 of 2 files failed to parse\n", stderr.String())
+
+	stdout.Reset()
+	stderr.Reset()
+	assert.Equal(t, 0, run([]string{"validate", "-mode", "lenient", dir}, stdout, stderr))
+	assert.Equal(t, filepath.Join(dir, "nested/bad.actor")+":2:5: warning: Unrecognised keyword 'Motto' treated as text\n", stdout.String())
+}
+
+func Test_ValidateReportsJUnit(t *testing.T) {
+
+	dir := writeActorFiles(t, map[string]string{
+		"good.actor": "Actor: Good\n",
+		"bad.actor":  "Actor: Bad\n    Motto: Be bad\n",
+	})
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	assert.Equal(t, 1, run([]string{"validate", "-format", "junit", dir}, stdout, stderr))
+	assert.Contains(t, stdout.String(), `<testsuites name="actor validate" tests="2" failures="1">`)
+	assert.Contains(t, stdout.String(), filepath.Join(dir, "bad.actor")+":2:5: error: Unrecognised keyword &#39;Motto&#39; (syntax)")
+}
+
+func Test_ValidateChecksTagsAgainstARegistry(t *testing.T) {
+
+	dir := writeActorFiles(t, map[string]string{
+		"tags.json":   `{"tags": [{"name": "admin"}]}`,
+		"typo.actor":  "@admn\nActor: Typo\n",
+		"admin.actor": "@admin\nActor: Admin\n",
+	})
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	assert.Equal(t, 1, run([]string{"validate", "-tags", filepath.Join(dir, "tags.json"), dir}, stdout, stderr))
+	assert.Equal(t, filepath.Join(dir, "typo.actor")+":1:1: error: Unknown tag '@admn', did you mean '@admin'?\n", stdout.String())
+
+	stderr.Reset()
+	assert.Equal(t, 2, run([]string{"validate", "-tags", filepath.Join(dir, "missing.json"), dir}, stdout, stderr))
+	assert.Contains(t, stderr.String(), "missing.json")
+}
+
+func Test_ValidateRejectsUnknownModes(t *testing.T) {
+	stderr := &bytes.Buffer{}
+	assert.Equal(t, 2, run([]string{"validate", "-mode", "picky"}, &bytes.Buffer{}, stderr))
+	assert.Equal(t, "actor validate: unknown mode 'picky'\n", stderr.String())
+}
+
+func Test_UnknownCommandsPrintUsage(t *testing.T) {
+	stderr := &bytes.Buffer{}
+	assert.Equal(t, 2, run([]string{"frobnicate"}, &bytes.Buffer{}, stderr))
+	assert.Contains(t, stderr.String(), "validate")
+}
@@ -0,0 +1,103 @@
+// Command actorgen generates Go constants for the actors, goals and tags of
+// .actor files, to be run by go generate:
+//
+//	//go:generate actorgen -o actors_gen.go ../features/actors
+//
+// The package defaults to the one go generate is run for.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/dryvercorp/actor"
+	"github.com/dryvercorp/actor/gen"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+
+	flags := flag.NewFlagSet("actorgen", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	pkg := flags.String("package", os.Getenv("GOPACKAGE"), "package name (default $GOPACKAGE, or actors)")
+	output := flags.String("o", "actors_gen.go", "output file, or - for stdout")
+
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: actorgen [-package name] [-o file] [file or directory...]")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	project, err := load(flags.Args())
+
+	if err != nil {
+		fmt.Fprintf(stderr, "actorgen: %s\n", err)
+		return 1
+	}
+
+	buf := &bytes.Buffer{}
+
+	if err := gen.Generate(buf, project, gen.Options{Package: *pkg}); err != nil {
+		fmt.Fprintf(stderr, "actorgen: %s\n", err)
+		return 1
+	}
+
+	if *output == "-" {
+		_, err = stdout.Write(buf.Bytes())
+	} else {
+		err = ioutil.WriteFile(*output, buf.Bytes(), 0644)
+	}
+
+	if err != nil {
+		fmt.Fprintf(stderr, "actorgen: %s\n", err)
+		return 1
+	}
+
+	return 0
+}
+
+// load parses the files named by the arguments, and the projects under the
+// directories
+func load(args []string) (*actor.Project, error) {
+
+	if len(args) == 0 {
+		args = []string{"."}
+	}
+
+	project := &actor.Project{Files: make([]*actor.ProjectFile, 0)}
+
+	for _, arg := range args {
+
+		info, err := os.Stat(arg)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !info.IsDir() {
+			project.Files = append(project.Files, actor.LoadProjectFile(arg, actor.ParserOptions{}))
+			continue
+		}
+
+		p, err := actor.LoadProject(arg, actor.ParserOptions{})
+
+		if err != nil {
+			return nil, err
+		}
+
+		project.Files = append(project.Files, p.Files...)
+	}
+
+	return project, nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ItWritesTheGeneratedFile(t *testing.T) {
+
+	dir := t.TempDir()
+
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "admin.actor"), []byte("Actor: Admin\n    Goal: Moderate\n"), 0644))
+
+	output := filepath.Join(dir, "actors_gen.go")
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+
+	assert.Equal(t, 0, run([]string{"-package", "people", "-o", output, dir}, stdout, stderr))
+	assert.Equal(t, "", stderr.String())
+
+	source, err := ioutil.ReadFile(output)
+	assert.Nil(t, err)
+	assert.Contains(t, string(source), "package people\n")
+	assert.Contains(t, string(source), "AdminModerate GoalName = \"Moderate\"")
+
+	assert.Equal(t, 0, run([]string{"-package", "people", "-o", "-", filepath.Join(dir, "admin.actor")}, stdout, stderr))
+	assert.Equal(t, string(source), stdout.String())
+}
+
+func Test_ItReportsFilesThatDontParse(t *testing.T) {
+
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "broken.actor")
+	assert.Nil(t, ioutil.WriteFile(path, []byte("Actor:\n"), 0644))
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+
+	assert.Equal(t, 1, run([]string{"-o", "-", path}, stdout, stderr))
+	assert.Equal(t, "actorgen: "+path+": [Line 0001:01] Actor keyword must be followed by an actor name\n", stderr.String())
+	assert.Equal(t, "", stdout.String())
+}
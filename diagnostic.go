@@ -0,0 +1,63 @@
+package actor
+
+import (
+	"fmt"
+
+	gherkin "github.com/cucumber/gherkin-go"
+)
+
+type Severity int
+
+const (
+	SeverityError Severity = iota
+	SeverityWarning
+)
+
+var severityNames = map[Severity]string{
+	SeverityError:   "error",
+	SeverityWarning: "warning",
+}
+
+func (s Severity) String() string {
+
+	if name, ok := severityNames[s]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *Severity) UnmarshalText(text []byte) error {
+
+	for severity, name := range severityNames {
+		if name == string(text) {
+			*s = severity
+			return nil
+		}
+	}
+
+	return fmt.Errorf("Unknown severity '%s'", text)
+}
+
+// Diagnostic is a positioned problem found while parsing
+type Diagnostic struct {
+	Location *gherkin.Location `json:"location"`
+	Severity Severity          `json:"severity"`
+	Message  string            `json:"message"`
+}
+
+func newDiagnostic(severity Severity, line, column int, message string) *Diagnostic {
+	return &Diagnostic{
+		Location: &gherkin.Location{Line: line, Column: column},
+		Severity: severity,
+		Message:  message,
+	}
+}
+
+func (d *Diagnostic) String() string {
+	return fmt.Sprintf("[Line %04d:%02d] %s: %s", d.Location.Line, d.Location.Column, d.Severity, d.Message)
+}
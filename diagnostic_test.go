@@ -0,0 +1,24 @@
+package actor
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_DiagnosticsPrintTheirPosition(t *testing.T) {
+	assert.Equal(t, "[Line 0012:05] warning: Something odd", newDiagnostic(SeverityWarning, 12, 5, "Something odd").String())
+}
+
+func Test_SeveritiesMarshalByName(t *testing.T) {
+
+	b, err := json.Marshal(newDiagnostic(SeverityError, 1, 1, "Broken"))
+	assert.Nil(t, err)
+	assert.Equal(t, `{"location":{"line":1,"column":1},"severity":"error","message":"Broken"}`, string(b))
+
+	var severity Severity
+	assert.Nil(t, severity.UnmarshalText([]byte("warning")))
+	assert.Equal(t, SeverityWarning, severity)
+	assert.NotNil(t, severity.UnmarshalText([]byte("fatal")))
+}
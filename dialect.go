@@ -0,0 +1,152 @@
+package actor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const defaultLanguage = "en"
+
+type dialect struct {
+	language string
+	name     string
+	native   string
+	keywords map[tokenKind][]string
+}
+
+var dialects = map[string]*dialect{
+	"en": {
+		language: "en",
+		name:     "English",
+		native:   "English",
+		keywords: map[tokenKind][]string{
+			token_actorDefinition: {"Actor"},
+			token_goals:           {"Goals"},
+			token_goal:            {"Goal"},
+			token_aliases:         {"Aliases"},
+		},
+	},
+	"fr": {
+		language: "fr",
+		name:     "French",
+		native:   "français",
+		keywords: map[tokenKind][]string{
+			token_actorDefinition: {"Acteur"},
+			token_goals:           {"Objectifs"},
+			token_goal:            {"Objectif"},
+			token_aliases:         {"Alias"},
+		},
+	},
+	"de": {
+		language: "de",
+		name:     "German",
+		native:   "Deutsch",
+		keywords: map[tokenKind][]string{
+			token_actorDefinition: {"Akteur"},
+			token_goals:           {"Ziele"},
+			token_goal:            {"Ziel"},
+			token_aliases:         {"Aliasse", "Alias"},
+		},
+	},
+	"es": {
+		language: "es",
+		name:     "Spanish",
+		native:   "español",
+		keywords: map[tokenKind][]string{
+			token_actorDefinition: {"Actor"},
+			token_goals:           {"Objetivos"},
+			token_goal:            {"Objetivo"},
+			token_aliases:         {"Alias"},
+		},
+	},
+	"pt": {
+		language: "pt",
+		name:     "Portuguese",
+		native:   "português",
+		keywords: map[tokenKind][]string{
+			token_actorDefinition: {"Ator", "Actor"},
+			token_goals:           {"Objetivos", "Objectivos"},
+			token_goal:            {"Objetivo", "Objectivo"},
+			token_aliases:         {"Apelidos", "Alias"},
+		},
+	},
+	"nl": {
+		language: "nl",
+		name:     "Dutch",
+		native:   "Nederlands",
+		keywords: map[tokenKind][]string{
+			token_actorDefinition: {"Actor"},
+			token_goals:           {"Doelen"},
+			token_goal:            {"Doel"},
+			token_aliases:         {"Aliassen", "Alias"},
+		},
+	},
+}
+
+// Languages returns the codes of every supported dialect, sorted.
+func Languages() []string {
+	languages := make([]string, 0, len(dialects))
+
+	for language := range dialects {
+		languages = append(languages, language)
+	}
+
+	sort.Strings(languages)
+
+	return languages
+}
+
+// Keywords returns every spelling of the keywords of a language's dialect,
+// the preferred spelling of each keyword first.
+func Keywords(language string) ([]string, error) {
+
+	d, err := dialectFor(language)
+
+	if err != nil {
+		return nil, err
+	}
+
+	keywords := make([]string, 0)
+
+	for _, kind := range []tokenKind{token_actorDefinition, token_goal, token_goals, token_aliases} {
+		keywords = append(keywords, d.keywords[kind]...)
+	}
+
+	return keywords, nil
+}
+
+func dialectFor(language string) (*dialect, error) {
+
+	if language == "" {
+		language = defaultLanguage
+	}
+
+	d, ok := dialects[strings.ToLower(language)]
+
+	if !ok {
+		return nil, fmt.Errorf("Unsupported language '%s'", language)
+	}
+
+	return d, nil
+}
+
+// keyword returns the preferred spelling of a keyword, as used by the writer
+func (d *dialect) keyword(kind tokenKind) string {
+	return d.keywords[kind][0]
+}
+
+func (d *dialect) kind(keyword string) (tokenKind, bool) {
+
+	keyword = strings.ToLower(strings.TrimSpace(keyword))
+
+	for kind, spellings := range d.keywords {
+		for _, spelling := range spellings {
+			if strings.ToLower(spelling) == keyword {
+				return kind, true
+			}
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,66 @@
+package actor
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_DialectsAreAvailableForSupportedLanguages(t *testing.T) {
+	assert.Equal(t, []string{"de", "en", "es", "fr", "nl", "pt"}, Languages())
+
+	for _, language := range Languages() {
+		d, err := dialectFor(language)
+		assert.Nil(t, err)
+
+		for _, kind := range []tokenKind{token_actorDefinition, token_goals, token_goal} {
+			assert.NotEmpty(t, d.keyword(kind), fmt.Sprintf("%s: missing %s keyword", language, kind))
+		}
+	}
+}
+
+func Test_DialectForUnknownLanguageFails(t *testing.T) {
+	d, err := dialectFor("xx")
+	assert.Nil(t, d)
+	assert.Equal(t, fmt.Errorf("Unsupported language 'xx'"), err)
+}
+
+func Test_DialectKeywordsAreCaseInsensitive(t *testing.T) {
+
+	var inputs = []struct {
+		language string
+		keyword  string
+		kind     tokenKind
+		ok       bool
+	}{
+		{language: "en", keyword: "actor", kind: token_actorDefinition, ok: true},
+		{language: "fr", keyword: "ACTEUR", kind: token_actorDefinition, ok: true},
+		{language: "de", keyword: "Ziele", kind: token_goals, ok: true},
+		{language: "pt", keyword: "Actor", kind: token_actorDefinition, ok: true},
+		{language: "nl", keyword: "doel", kind: token_goal, ok: true},
+		{language: "en", keyword: "ALIASES", kind: token_aliases, ok: true},
+		{language: "de", keyword: "alias", kind: token_aliases, ok: true},
+		{language: "fr", keyword: "Actor"},
+	}
+
+	for _, input := range inputs {
+		d, err := dialectFor(input.language)
+		assert.Nil(t, err)
+
+		kind, ok := d.kind(input.keyword)
+		assert.Equal(t, input.ok, ok, input.keyword)
+		assert.Equal(t, input.kind, kind, input.keyword)
+	}
+}
+
+func Test_KeywordsListsEverySpelling(t *testing.T) {
+
+	keywords, err := Keywords("pt")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Ator", "Actor", "Objetivo", "Objectivo", "Objetivos", "Objectivos", "Apelidos", "Alias"}, keywords)
+
+	keywords, err = Keywords("xx")
+	assert.Nil(t, keywords)
+	assert.NotNil(t, err)
+}
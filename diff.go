@@ -0,0 +1,537 @@
+package actor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	gherkin "github.com/cucumber/gherkin-go"
+)
+
+// ChangeKind is what a Change does to an actor
+type ChangeKind string
+
+const (
+	ChangeActorAdded   ChangeKind = "actor-added"
+	ChangeActorRemoved ChangeKind = "actor-removed"
+	ChangeActorRenamed ChangeKind = "actor-renamed"
+	ChangeLanguage     ChangeKind = "language-changed"
+	ChangeAliasAdded   ChangeKind = "alias-added"
+	ChangeAliasRemoved ChangeKind = "alias-removed"
+	ChangeTagAdded     ChangeKind = "tag-added"
+	ChangeTagRemoved   ChangeKind = "tag-removed"
+	ChangeBlurb        ChangeKind = "blurb-changed"
+	ChangeDocString    ChangeKind = "doc-string-changed"
+	ChangeDataTable    ChangeKind = "data-table-changed"
+	ChangeGoalAdded    ChangeKind = "goal-added"
+	ChangeGoalRemoved  ChangeKind = "goal-removed"
+	ChangeGoalRenamed  ChangeKind = "goal-renamed"
+	ChangeGoalRetagged ChangeKind = "goal-retagged"
+)
+
+// Change is a difference between two versions of an actor. From and To are
+// what changed, as written: a name, a tag, lines of blurb, the tags of a
+// goal separated by spaces, or a doc string's or table's lines.
+type Change struct {
+	Kind ChangeKind `json:"kind"`
+	Goal string     `json:"goal,omitempty"` // The goal changed, by its newer name
+	From string     `json:"from,omitempty"`
+	To   string     `json:"to,omitempty"`
+}
+
+// Changes is every difference between two versions of an actor
+type Changes []*Change
+
+// Diff compares two versions of an actor by their meaning rather than their
+// text: goals are matched by name wherever they are, so reordering them
+// isn't a change, and a goal whose name changed a little, or whose doc
+// string or table stayed the same, is renamed rather than removed and added.
+// Either actor may be nil, for a file that was added or removed.
+func Diff(a, b *Actor) Changes {
+
+	changes := make(Changes, 0)
+
+	add := func(c *Change) {
+		changes = append(changes, c)
+	}
+
+	switch {
+	case a == nil && b == nil:
+		return changes
+
+	case a == nil:
+		add(&Change{Kind: ChangeActorAdded, To: b.Name})
+		a = &Actor{Name: b.Name, Language: b.Language}
+
+	case b == nil:
+		add(&Change{Kind: ChangeActorRemoved, From: a.Name})
+		b = &Actor{Name: a.Name, Language: a.Language}
+
+	case a.Name != b.Name:
+		add(&Change{Kind: ChangeActorRenamed, From: a.Name, To: b.Name})
+	}
+
+	if language(a) != language(b) {
+		add(&Change{Kind: ChangeLanguage, From: language(a), To: language(b)})
+	}
+
+	removed, added := difference(aliasNames(a.Aliases), aliasNames(b.Aliases))
+
+	for _, alias := range removed {
+		add(&Change{Kind: ChangeAliasRemoved, From: alias})
+	}
+
+	for _, alias := range added {
+		add(&Change{Kind: ChangeAliasAdded, To: alias})
+	}
+
+	removed, added = difference(tagNames(a.Tags), tagNames(b.Tags))
+
+	for _, tag := range removed {
+		add(&Change{Kind: ChangeTagRemoved, From: "@" + tag})
+	}
+
+	for _, tag := range added {
+		add(&Change{Kind: ChangeTagAdded, To: "@" + tag})
+	}
+
+	if from, to := strings.Join(a.Blurb, "\n"), strings.Join(b.Blurb, "\n"); from != to {
+		add(&Change{Kind: ChangeBlurb, From: from, To: to})
+	}
+
+	diffContent("", a.DocString, b.DocString, a.DataTable, b.DataTable, add)
+	diffGoals(a.Goals, b.Goals, add)
+
+	return changes
+}
+
+func language(a *Actor) string {
+
+	if a.Language == "" {
+		return defaultLanguage
+	}
+
+	return a.Language
+}
+
+func diffContent(goal string, fromDocString, toDocString *DocString, fromTable, toTable *DataTable, add func(*Change)) {
+
+	if from, to := docStringText(fromDocString), docStringText(toDocString); from != to {
+		add(&Change{Kind: ChangeDocString, Goal: goal, From: from, To: to})
+	}
+
+	if from, to := tableText(fromTable), tableText(toTable); from != to {
+		add(&Change{Kind: ChangeDataTable, Goal: goal, From: from, To: to})
+	}
+}
+
+// diffGoals matches the goals of each version, then lists those removed,
+// added and changed
+func diffGoals(from, to []*Goal, add func(*Change)) {
+
+	pairs := matchGoals(from, to)
+	paired := make(map[*Goal]bool)
+
+	for _, a := range pairs {
+		paired[a] = true
+	}
+
+	for _, a := range from {
+		if !paired[a] {
+			add(&Change{Kind: ChangeGoalRemoved, Goal: a.Name})
+		}
+	}
+
+	for _, b := range to {
+
+		if a, ok := pairs[b]; ok {
+			diffGoal(a, b, add)
+		} else {
+			add(&Change{Kind: ChangeGoalAdded, Goal: b.Name})
+		}
+	}
+}
+
+// matchGoals pairs each goal of the newer version with the one it was in
+// the older: by name, then, for those left over, by being similar enough to
+// have been renamed, and more similar to each other than to any other goal
+func matchGoals(from, to []*Goal) map[*Goal]*Goal {
+
+	pairs := make(map[*Goal]*Goal)
+	paired := make(map[*Goal]bool)
+
+	for _, b := range to {
+		for _, a := range from {
+			if !paired[a] && normaliseName(a.Name) == normaliseName(b.Name) {
+				pairs[b], paired[a] = a, true
+				break
+			}
+		}
+	}
+
+	unpaired := func(goals []*Goal, isPaired func(*Goal) bool) []*Goal {
+
+		left := make([]*Goal, 0)
+
+		for _, g := range goals {
+			if !isPaired(g) {
+				left = append(left, g)
+			}
+		}
+
+		return left
+	}
+
+	for _, a := range from {
+
+		if paired[a] {
+			continue
+		}
+
+		b := closestGoal(a, unpaired(to, func(g *Goal) bool { _, ok := pairs[g]; return ok }))
+
+		if b != nil && closestGoal(b, unpaired(from, func(g *Goal) bool { return paired[g] })) == a {
+			pairs[b], paired[a] = a, true
+		}
+	}
+
+	return pairs
+}
+
+// closestGoal returns the goal most similar to g, or nil if none is similar
+// or more than one is as similar
+func closestGoal(g *Goal, goals []*Goal) *Goal {
+
+	var best *Goal
+	bestDistance, tied := 0, false
+
+	for _, other := range goals {
+
+		distance, ok := similarGoals(g, other)
+
+		switch {
+		case !ok:
+		case best == nil || distance < bestDistance:
+			best, bestDistance, tied = other, distance, false
+		case distance == bestDistance:
+			tied = true
+		}
+	}
+
+	if tied {
+		return nil
+	}
+
+	return best
+}
+
+func diffGoal(a, b *Goal, add func(*Change)) {
+
+	if a.Name != b.Name {
+		add(&Change{Kind: ChangeGoalRenamed, Goal: b.Name, From: a.Name, To: b.Name})
+	}
+
+	if removed, added := difference(tagNames(a.Tags), tagNames(b.Tags)); len(removed) > 0 || len(added) > 0 {
+		add(&Change{Kind: ChangeGoalRetagged, Goal: b.Name, From: tagList(a.Tags), To: tagList(b.Tags)})
+	}
+
+	diffContent(b.Name, a.DocString, b.DocString, a.DataTable, b.DataTable, add)
+}
+
+// similarGoals reports whether one goal could have been renamed to the
+// other: their names differ by at most a third of their characters, or they
+// have the same doc string or table
+func similarGoals(a, b *Goal) (int, bool) {
+
+	from, to := normaliseName(a.Name), normaliseName(b.Name)
+	distance := levenshtein(from, to)
+	length := utf8.RuneCountInString(from)
+
+	if l := utf8.RuneCountInString(to); l > length {
+		length = l
+	}
+
+	if distance*3 <= length {
+		return distance, true
+	}
+
+	if (a.DocString != nil && docStringText(a.DocString) == docStringText(b.DocString)) ||
+		(a.DataTable != nil && tableText(a.DataTable) == tableText(b.DataTable)) {
+		return distance, true
+	}
+
+	return 0, false
+}
+
+// difference returns what's only in from, and what's only in to, in order
+func difference(from, to []string) (removed, added []string) {
+
+	in := func(list []string, s string) bool {
+		for _, l := range list {
+			if l == s {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	for _, s := range from {
+		if !in(to, s) {
+			removed = append(removed, s)
+		}
+	}
+
+	for _, s := range to {
+		if !in(from, s) {
+			added = append(added, s)
+		}
+	}
+
+	return
+}
+
+func aliasNames(aliases []*Alias) []string {
+
+	names := make([]string, 0, len(aliases))
+
+	for _, alias := range aliases {
+		names = append(names, alias.Name)
+	}
+
+	return names
+}
+
+func tagNames(tags []*gherkin.Tag) []string {
+
+	names := make([]string, 0, len(tags))
+
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+
+	return names
+}
+
+// tagList writes tags as they appear on a tag line, sorted as their order
+// doesn't matter
+func tagList(tags []*gherkin.Tag) string {
+
+	names := tagNames(tags)
+	sort.Strings(names)
+
+	for i, name := range names {
+		names[i] = "@" + name
+	}
+
+	return strings.Join(names, " ")
+}
+
+func docStringText(d *DocString) string {
+
+	if d == nil {
+		return ""
+	}
+
+	return docStringDelimiters[0] + d.ContentType + "\n" + d.Content + "\n" + docStringDelimiters[0]
+}
+
+func tableText(t *DataTable) string {
+
+	if t == nil {
+		return ""
+	}
+
+	rows := make([]string, 0, len(t.Rows))
+
+	for _, row := range t.Rows {
+
+		cells := make([]string, 0, len(row.Cells))
+
+		for _, cell := range row.Cells {
+			cells = append(cells, escapeTableCell(cell.Value))
+		}
+
+		rows = append(rows, "| "+strings.Join(cells, " | ")+" |")
+	}
+
+	return strings.Join(rows, "\n")
+}
+
+func (c *Change) String() string {
+
+	subject := ""
+
+	if c.Goal != "" {
+		subject = fmt.Sprintf("Goal '%s': ", c.Goal)
+	}
+
+	switch c.Kind {
+
+	case ChangeActorAdded:
+		return fmt.Sprintf("Actor '%s' added", c.To)
+
+	case ChangeActorRemoved:
+		return fmt.Sprintf("Actor '%s' removed", c.From)
+
+	case ChangeActorRenamed:
+		return fmt.Sprintf("Actor renamed from '%s' to '%s'", c.From, c.To)
+
+	case ChangeLanguage:
+		return fmt.Sprintf("Language changed from '%s' to '%s'", c.From, c.To)
+
+	case ChangeAliasAdded:
+		return fmt.Sprintf("Alias '%s' added", c.To)
+
+	case ChangeAliasRemoved:
+		return fmt.Sprintf("Alias '%s' removed", c.From)
+
+	case ChangeTagAdded:
+		return fmt.Sprintf("Tag '%s' added", c.To)
+
+	case ChangeTagRemoved:
+		return fmt.Sprintf("Tag '%s' removed", c.From)
+
+	case ChangeBlurb:
+		return "Blurb changed" + lineChanges(c.From, c.To)
+
+	case ChangeDocString:
+		return subject + "Doc string changed" + lineChanges(c.From, c.To)
+
+	case ChangeDataTable:
+		return subject + "Data table changed" + lineChanges(c.From, c.To)
+
+	case ChangeGoalAdded:
+		return fmt.Sprintf("Goal '%s' added", c.Goal)
+
+	case ChangeGoalRemoved:
+		return fmt.Sprintf("Goal '%s' removed", c.Goal)
+
+	case ChangeGoalRenamed:
+		return fmt.Sprintf("Goal renamed from '%s' to '%s'", c.From, c.To)
+
+	case ChangeGoalRetagged:
+		return fmt.Sprintf("%sTags changed from %s to %s", subject, describeTags(c.From), describeTags(c.To))
+	}
+
+	return fmt.Sprintf("%s%s", subject, c.Kind)
+}
+
+func describeTags(tags string) string {
+
+	if tags == "" {
+		return "none"
+	}
+
+	return tags
+}
+
+// lineChanges lists the lines removed and added, each on a line of its own
+// under the change
+func lineChanges(from, to string) string {
+
+	lines := ""
+
+	for _, l := range diffLines(splitLines(from), splitLines(to)) {
+		if l.op != ' ' {
+			lines += fmt.Sprintf("\n    %c %s", l.op, l.text)
+		}
+	}
+
+	return lines
+}
+
+func splitLines(text string) []string {
+
+	if text == "" {
+		return nil
+	}
+
+	return strings.Split(text, "\n")
+}
+
+// diffLine is a line kept (' '), removed ('-') or added ('+')
+type diffLine struct {
+	op   byte
+	text string
+}
+
+// diffLines finds the fewest lines to remove and add to turn one list of
+// lines into the other, from their longest common subsequence
+func diffLines(from, to []string) []diffLine {
+
+	// common[i][j] is the length of the longest common subsequence of
+	// from[i:] and to[j:]
+	common := make([][]int, len(from)+1)
+
+	for i := range common {
+		common[i] = make([]int, len(to)+1)
+	}
+
+	for i := len(from) - 1; i >= 0; i-- {
+		for j := len(to) - 1; j >= 0; j-- {
+			if from[i] == to[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else if common[i+1][j] >= common[i][j+1] {
+				common[i][j] = common[i+1][j]
+			} else {
+				common[i][j] = common[i][j+1]
+			}
+		}
+	}
+
+	lines := make([]diffLine, 0, len(from)+len(to))
+	i, j := 0, 0
+
+	for i < len(from) && j < len(to) {
+		switch {
+		case from[i] == to[j]:
+			lines = append(lines, diffLine{' ', from[i]})
+			i, j = i+1, j+1
+		case common[i+1][j] >= common[i][j+1]:
+			lines = append(lines, diffLine{'-', from[i]})
+			i++
+		default:
+			lines = append(lines, diffLine{'+', to[j]})
+			j++
+		}
+	}
+
+	for ; i < len(from); i++ {
+		lines = append(lines, diffLine{'-', from[i]})
+	}
+
+	for ; j < len(to); j++ {
+		lines = append(lines, diffLine{'+', to[j]})
+	}
+
+	return lines
+}
+
+// WriteText writes each change on a line of its own, with the lines of a
+// changed blurb, doc string or table indented below it
+func (c Changes) WriteText(w io.Writer) error {
+
+	for _, change := range c {
+		if _, err := fmt.Fprintln(w, change); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c Changes) WriteJSON(w io.Writer) error {
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	if c == nil {
+		c = Changes{}
+	}
+
+	return encoder.Encode(c)
+}
@@ -0,0 +1,194 @@
+package actor
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func parseForDiff(t *testing.T, text string) *Actor {
+
+	a, err := NewParser(bytes.NewBufferString(text)).Parse()
+	assert.Nil(t, err)
+
+	return a
+}
+
+func Test_ReorderedGoalsAreNotAChange(t *testing.T) {
+
+	a := parseForDiff(t, "Actor: Shopper\n    @x\n    Goal: Pay\n    Goals:\n        Browse\n        Buy\n")
+	b := parseForDiff(t, "Actor: Shopper\n    Goals:\n        Buy\n    @x\n    Goal: Pay\n    Goals:\n        browse\n")
+
+	assert.Equal(t, Changes{
+		{Kind: ChangeGoalRenamed, Goal: "browse", From: "Browse", To: "browse"},
+	}, Diff(a, b))
+
+	assert.Equal(t, Changes{}, Diff(a, a))
+}
+
+func Test_OnlyCloseGoalsAreRenamed(t *testing.T) {
+
+	tests := []struct {
+		from, to string
+		expected Changes
+	}{
+		{"Log in", "Log out", Changes{
+			{Kind: ChangeGoalRemoved, Goal: "Log in"},
+			{Kind: ChangeGoalAdded, Goal: "Log out"},
+		}},
+		{"Pay the bill", "Pay the bills", Changes{
+			{Kind: ChangeGoalRenamed, Goal: "Pay the bills", From: "Pay the bill", To: "Pay the bills"},
+		}},
+		// Neither goal is closer than the other
+		{"Pay the bill", "Pay the bills\n        Pay the pill", Changes{
+			{Kind: ChangeGoalRemoved, Goal: "Pay the bill"},
+			{Kind: ChangeGoalAdded, Goal: "Pay the bills"},
+			{Kind: ChangeGoalAdded, Goal: "Pay the pill"},
+		}},
+		// Nor is either goal it could have been renamed from
+		{"Pay the bills\n        Pay the pill", "Pay the bill", Changes{
+			{Kind: ChangeGoalRemoved, Goal: "Pay the bills"},
+			{Kind: ChangeGoalRemoved, Goal: "Pay the pill"},
+			{Kind: ChangeGoalAdded, Goal: "Pay the bill"},
+		}},
+	}
+
+	for _, test := range tests {
+
+		a := parseForDiff(t, "Actor: Shopper\n    Goals:\n        "+test.from+"\n")
+		b := parseForDiff(t, "Actor: Shopper\n    Goals:\n        "+test.to+"\n")
+
+		assert.Equal(t, test.expected, Diff(a, b), test.from)
+	}
+}
+
+func Test_ItListsEveryChangeToAnActor(t *testing.T) {
+
+	a := parseForDiff(t, `@staff @old
+Actor: Support agent
+    Aliases:
+        Helpdesk
+    Handles refunds
+    Answers the phone
+
+    @mobile
+    Goal: Refund an order
+        | amount | limit |
+        | 10     | 100   |
+
+    Goal: Escalate complaints
+        """
+        To a manager
+        """
+
+    Goals:
+        Answer tickets
+        Close tickets
+`)
+
+	b := parseForDiff(t, `# language: fr
+@staff @new
+Acteur: Customer support agent
+    Alias:
+        Customer service
+    Handles refunds
+    Answers email
+
+    @mobile @web
+    Objectif: Refund orders
+        | amount | limit |
+        | 10     | 200   |
+
+    Objectif: Send complaints upwards
+        """
+        To a manager
+        """
+
+    Objectifs:
+        Answer tickets
+        Chat with customers
+`)
+
+	changes := Diff(a, b)
+
+	assert.Equal(t, Changes{
+		{Kind: ChangeActorRenamed, From: "Support agent", To: "Customer support agent"},
+		{Kind: ChangeLanguage, From: "en", To: "fr"},
+		{Kind: ChangeAliasRemoved, From: "Helpdesk"},
+		{Kind: ChangeAliasAdded, To: "Customer service"},
+		{Kind: ChangeTagRemoved, From: "@old"},
+		{Kind: ChangeTagAdded, To: "@new"},
+		{Kind: ChangeBlurb, From: "Handles refunds\nAnswers the phone", To: "Handles refunds\nAnswers email"},
+		{Kind: ChangeGoalRemoved, Goal: "Close tickets"},
+		{Kind: ChangeGoalRenamed, Goal: "Refund orders", From: "Refund an order", To: "Refund orders"},
+		{Kind: ChangeGoalRetagged, Goal: "Refund orders", From: "@mobile", To: "@mobile @web"},
+		{Kind: ChangeDataTable, Goal: "Refund orders", From: "| amount | limit |\n| 10 | 100 |", To: "| amount | limit |\n| 10 | 200 |"},
+		{Kind: ChangeGoalRenamed, Goal: "Send complaints upwards", From: "Escalate complaints", To: "Send complaints upwards"},
+		{Kind: ChangeGoalAdded, Goal: "Chat with customers"},
+	}, changes)
+
+	buf := &bytes.Buffer{}
+	assert.Nil(t, changes.WriteText(buf))
+
+	assert.Equal(t, `Actor renamed from 'Support agent' to 'Customer support agent'
+Language changed from 'en' to 'fr'
+Alias 'Helpdesk' removed
+Alias 'Customer service' added
+Tag '@old' removed
+Tag '@new' added
+Blurb changed
+    - Answers the phone
+    + Answers email
+Goal 'Close tickets' removed
+Goal renamed from 'Refund an order' to 'Refund orders'
+Goal 'Refund orders': Tags changed from @mobile to @mobile @web
+Goal 'Refund orders': Data table changed
+    - | 10 | 100 |
+    + | 10 | 200 |
+Goal renamed from 'Escalate complaints' to 'Send complaints upwards'
+Goal 'Chat with customers' added
+`, buf.String())
+}
+
+func Test_ItDiffsAddedAndRemovedActors(t *testing.T) {
+
+	a := parseForDiff(t, "@x\nActor: Visitor\n    \"\"\"\n    Reads\n    \"\"\"\n    Goal: Read\n")
+
+	assert.Equal(t, Changes{
+		{Kind: ChangeActorAdded, To: "Visitor"},
+		{Kind: ChangeTagAdded, To: "@x"},
+		{Kind: ChangeDocString, To: "\"\"\"\nReads\n\"\"\""},
+		{Kind: ChangeGoalAdded, Goal: "Read"},
+	}, Diff(nil, a))
+
+	changes := Diff(a, nil)
+	assert.Equal(t, ChangeActorRemoved, changes[0].Kind)
+	assert.Equal(t, 4, len(changes))
+	assert.Equal(t, "Doc string changed\n    - \"\"\"\n    - Reads\n    - \"\"\"", changes[2].String())
+
+	assert.Equal(t, Changes{}, Diff(nil, nil))
+}
+
+func Test_ItWritesChangesAsJSON(t *testing.T) {
+
+	buf := &bytes.Buffer{}
+	assert.Nil(t, Changes{
+		{Kind: ChangeGoalRetagged, Goal: "Pay", To: "@x"},
+	}.WriteJSON(buf))
+
+	assert.Equal(t, `[
+  {
+    "kind": "goal-retagged",
+    "goal": "Pay",
+    "to": "@x"
+  }
+]
+`, buf.String())
+
+	buf.Reset()
+	assert.Nil(t, Changes(nil).WriteJSON(buf))
+	assert.Equal(t, "[]\n", buf.String())
+
+	assert.Equal(t, "Goal 'Pay': Tags changed from none to @x", (&Change{Kind: ChangeGoalRetagged, Goal: "Pay", To: "@x"}).String())
+}
@@ -0,0 +1,147 @@
+package actor
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	gherkin "github.com/cucumber/gherkin-go"
+)
+
+// Edit replaces the text between two locations, as when text is typed over a
+// selection. Locations are 1-based, with columns counted in characters, and
+// the end is exclusive.
+type Edit struct {
+	Start gherkin.Location
+	End   gherkin.Location
+	Text  string
+}
+
+// EditError is an edit that couldn't be made, as it isn't within the text.
+// Edit is its 1-based position in the edits given to Apply.
+type EditError struct {
+	Edit    int
+	Problem string
+}
+
+func (e *EditError) Error() string {
+	return fmt.Sprintf("Edit %d: %s", e.Edit, e.Problem)
+}
+
+// Document is the text of a .actor file that is parsed again after every set
+// of edits. Subtrees of lines that the edits don't touch are reused from the
+// previous parse rather than lexed again.
+type Document struct {
+	options     ParserOptions
+	lines       []string
+	tree        lexerTree
+	indentation []*Diagnostic
+	actor       *Actor
+	diagnostics []*Diagnostic
+	err         error
+}
+
+func NewDocument(text string, options ParserOptions) *Document {
+
+	d := &Document{options: options, lines: strings.Split(text, "\n")}
+	d.parse(nil)
+
+	return d
+}
+
+func (d *Document) Text() string {
+	return strings.Join(d.lines, "\n")
+}
+
+// Actor returns the actor of the last parse, which is nil if it failed
+func (d *Document) Actor() *Actor {
+	return d.actor
+}
+
+// Err returns the error of the last parse
+func (d *Document) Err() error {
+	return d.err
+}
+
+func (d *Document) Diagnostics() []*Diagnostic {
+	return d.diagnostics
+}
+
+// Apply makes each edit in turn, against the text left by the edit before,
+// and parses the result. An edit outside the text is an *EditError, and
+// leaves the document unchanged; any other error is from parsing the result.
+func (d *Document) Apply(edits ...Edit) (*Actor, error) {
+
+	lines := append([]string(nil), d.lines...)
+	origin := make([]int, len(lines))
+
+	for i := range origin {
+		origin[i] = i + 1
+	}
+
+	for i, edit := range edits {
+
+		start, err := offset(lines, edit.Start)
+
+		if err != nil {
+			return nil, &EditError{Edit: i + 1, Problem: err.Error()}
+		}
+
+		end, err := offset(lines, edit.End)
+
+		if err != nil {
+			return nil, &EditError{Edit: i + 1, Problem: err.Error()}
+		}
+
+		if edit.End.Line < edit.Start.Line || (edit.End.Line == edit.Start.Line && end < start) {
+			return nil, &EditError{Edit: i + 1, Problem: "End is before the start"}
+		}
+
+		first, last := edit.Start.Line-1, edit.End.Line-1
+		replacement := strings.Split(lines[first][:start]+edit.Text+lines[last][end:], "\n")
+
+		lines = append(lines[:first], append(replacement, lines[last+1:]...)...)
+		origin = append(origin[:first], append(make([]int, len(replacement)), origin[last+1:]...)...)
+	}
+
+	d.lines = lines
+	d.parse(newLexerHistory(d.tree, origin, d.indentation))
+
+	return d.actor, d.err
+}
+
+func (d *Document) parse(history *lexerHistory) {
+
+	p := NewParserWithOptions(strings.NewReader(d.Text()), d.options).(*parser)
+	p.history = history
+
+	d.actor, d.err = p.Parse()
+	d.diagnostics = p.Diagnostics()
+	d.tree, d.indentation = p.lexer.lines, p.lexer.indentDiagnostics()
+}
+
+// offset converts a location to a byte offset within its line
+func offset(lines []string, location gherkin.Location) (int, error) {
+
+	if location.Line < 1 || location.Line > len(lines) {
+		return 0, fmt.Errorf("Line %d is outside the text", location.Line)
+	}
+
+	text := lines[location.Line-1]
+	characters := location.Column - 1
+
+	if characters < 0 || characters > utf8.RuneCountInString(text) {
+		return 0, fmt.Errorf("Column %d is outside line %d", location.Column, location.Line)
+	}
+
+	for i := range text {
+
+		if characters == 0 {
+			return i, nil
+		}
+
+		characters--
+	}
+
+	return len(text), nil
+}
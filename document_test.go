@@ -0,0 +1,196 @@
+package actor
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	gherkin "github.com/cucumber/gherkin-go"
+	"github.com/stretchr/testify/assert"
+)
+
+const documentText = `# language: en
+@admin
+Actor: Administrator
+    Manages the site
+    """
+    * Users
+      * Guests
+    """
+
+    @moderation
+    Goal: Moderate comments
+        | within | 1 day |
+
+    Goals:
+        Publish articles
+        Retire articles
+`
+
+// assertParsesAsNew checks that a document matches a parse of its text from
+// scratch
+func assertParsesAsNew(t *testing.T, d *Document, options ParserOptions, message string) {
+
+	parser := NewParserWithOptions(bytes.NewBufferString(d.Text()), options)
+	actor, err := parser.Parse()
+
+	assert.Equal(t, err, d.Err(), message)
+	assert.Equal(t, actor, d.Actor(), message)
+	assert.Equal(t, parser.Diagnostics(), d.Diagnostics(), message)
+}
+
+func at(line, column int) gherkin.Location {
+	return gherkin.Location{Line: line, Column: column}
+}
+
+func Test_ItAppliesEdits(t *testing.T) {
+
+	d := NewDocument(documentText, ParserOptions{})
+	assert.Nil(t, d.Err())
+
+	actor, err := d.Apply(
+		Edit{Start: at(3, 8), End: at(3, 21), Text: "Site administrator"},
+		Edit{Start: at(15, 9), End: at(15, 9), Text: "Write and "},
+		Edit{Start: at(4, 1), End: at(4, 1), Text: "    Looks after\n"},
+	)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Site administrator", actor.Name)
+	assert.Equal(t, []string{"Looks after", "Manages the site"}, actor.Blurb)
+	assert.Equal(t, "Write and Publish articles", actor.Goals[1].Name)
+	assert.Equal(t, &gherkin.Location{Line: 16, Column: 9}, actor.Goals[1].Location)
+	assert.Equal(t, &gherkin.Location{Line: 13, Column: 9}, actor.Goals[0].DataTable.Location)
+	assertParsesAsNew(t, d, ParserOptions{}, "After edits")
+
+	// Errors are reported and recovered from
+	_, err = d.Apply(Edit{Start: at(12, 5), End: at(12, 9), Text: "Motto"})
+	assert.Equal(t, "[Line 0012:05] Unrecognised keyword 'Motto'", err.Error())
+	_, isEditError := err.(*EditError)
+	assert.False(t, isEditError)
+	assertParsesAsNew(t, d, ParserOptions{}, "After an error")
+
+	_, err = d.Apply(Edit{Start: at(12, 5), End: at(12, 10), Text: "Goal"})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(d.Actor().Goals))
+}
+
+func Test_ItRejectsEditsOutsideTheText(t *testing.T) {
+
+	d := NewDocument("Actor: Administrator\n", ParserOptions{})
+
+	for _, test := range []struct {
+		edit     Edit
+		expected string
+	}{
+		{Edit{Start: at(0, 1), End: at(1, 1)}, "Edit 1: Line 0 is outside the text"},
+		{Edit{Start: at(1, 1), End: at(3, 1)}, "Edit 1: Line 3 is outside the text"},
+		{Edit{Start: at(1, 1), End: at(1, 22)}, "Edit 1: Column 22 is outside line 1"},
+		{Edit{Start: at(1, 5), End: at(1, 2)}, "Edit 1: End is before the start"},
+	} {
+		_, err := d.Apply(test.edit)
+		assert.Equal(t, test.expected, err.Error())
+		assert.IsType(t, &EditError{}, err)
+	}
+
+	assert.Equal(t, "Actor: Administrator\n", d.Text())
+}
+
+func Test_ItReusesUnchangedSubtrees(t *testing.T) {
+
+	d := NewDocument(documentText, ParserOptions{})
+	goal := d.tree[2].children[3]
+
+	// Editing the actor's blurb leaves the goal alone, although it moves
+	d.Apply(Edit{Start: at(4, 1), End: at(4, 1), Text: "    Looks after\n"})
+
+	assert.True(t, goal == d.tree[2].children[4])
+	assert.Equal(t, 12, goal.line)
+
+	// A line indented under the goal changes it
+	d.Apply(Edit{Start: at(13, 1), End: at(13, 1), Text: "        | every | day |\n"})
+
+	assert.False(t, goal == d.tree[2].children[4])
+	assertParsesAsNew(t, d, ParserOptions{}, "After extending a subtree")
+}
+
+func Test_ItMatchesAParseFromScratch(t *testing.T) {
+
+	fragments := []string{"", "\n", "    ", "\t", "Goal: New", "Goals:\n", "@tag ", "# note", `"""`, "| a | b |", "text", "Motto: ", "\n        Nested\n"}
+	random := rand.New(rand.NewSource(1))
+
+	for _, options := range []ParserOptions{{}, {Mode: ModeLenient}, {Mode: ModeStrict}} {
+
+		d := NewDocument(documentText, options)
+
+		for i := 0; i < 500; i++ {
+
+			lines := strings.Split(d.Text(), "\n")
+			start := at(random.Intn(len(lines))+1, 1)
+			start.Column = random.Intn(len([]rune(lines[start.Line-1]))+1) + 1
+
+			end := start
+
+			if random.Intn(3) == 0 {
+				end.Line = start.Line + random.Intn(len(lines)-start.Line+1)
+				end.Column = random.Intn(len([]rune(lines[end.Line-1]))+1) + 1
+
+				if end.Line == start.Line && end.Column < start.Column {
+					end.Column = start.Column
+				}
+			}
+
+			edit := Edit{Start: start, End: end, Text: fragments[random.Intn(len(fragments))]}
+			d.Apply(edit)
+
+			assertParsesAsNew(t, d, options, fmt.Sprintf("Mode %d, edit %d: %+v", options.Mode, i, edit))
+
+			// Start again whenever the edits have left too little to work with
+			if len(d.Text()) < 50 || len(d.Text()) > 2000 {
+				d = NewDocument(documentText, options)
+			}
+		}
+	}
+}
+
+func benchmarkText(goals int) string {
+
+	text := "Actor: Benchmark\n    A large actor\n\n"
+
+	for i := 0; i < goals; i++ {
+		text += fmt.Sprintf("    @goal%d\n    Goal: Goal number %d\n        \"\"\"\n        Notes on goal %d\n        \"\"\"\n        | key | value %d |\n\n", i, i, i, i)
+	}
+
+	return text
+}
+
+func Benchmark_Parse(b *testing.B) {
+
+	text := benchmarkText(500)
+
+	for i := 0; i < b.N; i++ {
+		if _, err := NewParser(bytes.NewBufferString(text + "    Typed")).Parse(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func Benchmark_DocumentApply(b *testing.B) {
+
+	d := NewDocument(benchmarkText(500), ParserOptions{})
+	last := len(d.lines)
+
+	for i := 0; i < b.N; i++ {
+
+		text := "    Typed"
+
+		if i%2 == 1 {
+			text = ""
+		}
+
+		if _, err := d.Apply(Edit{Start: at(last, 1), End: at(last, len(d.lines[last-1])+1), Text: text}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
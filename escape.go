@@ -0,0 +1,89 @@
+package actor
+
+import "strings"
+
+// Characters that have a meaning in a line and can be escaped with a backslash
+const escapable = "\\#@:|\"`"
+
+func isEscapable(c byte) bool {
+	return strings.IndexByte(escapable, c) >= 0
+}
+
+// indexUnescaped returns the index of the first c not preceded by an escape
+func indexUnescaped(s string, c byte) int {
+
+	for i := 0; i < len(s); i++ {
+
+		if s[i] == '\\' && i+1 < len(s) && isEscapable(s[i+1]) {
+			i++
+			continue
+		}
+
+		if s[i] == c {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// unescape removes the backslash from escaped characters. A backslash before
+// any other character is kept as it is, so paths and the like survive.
+func unescape(s string) string {
+
+	if strings.IndexByte(s, '\\') < 0 {
+		return s
+	}
+
+	unescaped := make([]byte, 0, len(s))
+
+	for i := 0; i < len(s); i++ {
+
+		if s[i] == '\\' && i+1 < len(s) && isEscapable(s[i+1]) {
+			i++
+		}
+
+		unescaped = append(unescaped, s[i])
+	}
+
+	return string(unescaped)
+}
+
+// escapeValue escapes a keyword value, such as a goal name after Goal:
+func escapeValue(s string) string {
+
+	escaped := make([]byte, 0, len(s))
+
+	for i := 0; i < len(s); i++ {
+
+		if (s[i] == '\\' && i+1 < len(s) && isEscapable(s[i+1])) || s[i] == '#' {
+			escaped = append(escaped, '\\')
+		}
+
+		escaped = append(escaped, s[i])
+	}
+
+	return string(escaped)
+}
+
+// escapeText escapes a line of text, such as a blurb or a goal in a list, so
+// that it isn't mistaken for a tag, keyword, table row or doc string.
+func escapeText(s string) string {
+
+	escaped := escapeValue(s)
+
+	// A colon within the first word would start a keyword
+	word := strings.IndexAny(escaped, " \t")
+
+	if word < 0 {
+		word = len(escaped)
+	}
+
+	escaped = strings.Replace(escaped[:word], ":", `\:`, -1) + escaped[word:]
+
+	if strings.HasPrefix(escaped, "@") || strings.HasPrefix(escaped, "|") || lineContent(escaped).docStringDelimiter() != "" {
+		escaped = `\` + escaped
+	}
+
+	return escaped
+}
@@ -0,0 +1,82 @@
+package actor
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var trickyText = []string{
+	"Reach #1 in search",
+	"Note: this line has a colon",
+	"Ratio:1:2 is fine",
+	"http://example.com",
+	"@mentions are not tags",
+	"| not a table |",
+	`""" not a doc string`,
+	"``` not a doc string either",
+	`C:\Users\someone`,
+	`a backslash before a hash \#`,
+	`trailing backslash \`,
+	`double \\ backslash`,
+}
+
+func Test_UnescapeRemovesOnlyMeaningfulEscapes(t *testing.T) {
+
+	var inputs = []struct {
+		input  string
+		output string
+	}{
+		{input: `Reach \#1`, output: "Reach #1"},
+		{input: `\@home`, output: "@home"},
+		{input: `Note\: text`, output: "Note: text"},
+		{input: `C:\Users`, output: `C:\Users`},
+		{input: `\\\#`, output: `\#`},
+		{input: `end\`, output: `end\`},
+	}
+
+	for _, input := range inputs {
+		assert.Equal(t, input.output, unescape(input.input))
+	}
+}
+
+func Test_IndexUnescapedSkipsEscapedCharacters(t *testing.T) {
+	assert.Equal(t, 9, indexUnescaped(`a \# b \\# c`, '#'))
+	assert.Equal(t, -1, indexUnescaped(`a \: b`, ':'))
+}
+
+func Test_EscapedTextTokenisesBackToTheOriginal(t *testing.T) {
+
+	tkn := newTokeniser()
+
+	for _, text := range trickyText {
+
+		tokens, err := tkn.tokenise(&line{content: lineContent(escapeText(text))})
+		assert.Nil(t, err, text)
+		assert.Equal(t, []token{{kind: token_text, content: text}}, tokens, text)
+
+		tokens, err = tkn.tokenise(&line{content: lineContent("Goal: " + escapeValue(text))})
+		assert.Nil(t, err, text)
+		assert.Equal(t, []token{{kind: token_goal, content: text, column: 6}}, tokens, text)
+	}
+}
+
+func Test_ArbitraryTextRoundTripsThroughAFile(t *testing.T) {
+
+	actor := NewActor()
+	actor.Name = "Number #1: the actor"
+	actor.Blurb = trickyText
+
+	for _, text := range trickyText {
+		actor.Goals = append(actor.Goals, &Goal{Name: text})
+	}
+
+	buf := &bytes.Buffer{}
+	assert.Nil(t, actor.Write(buf))
+
+	read_actor, err := NewParser(buf).Parse()
+	assert.Nil(t, err)
+
+	compareActors(t, actor, read_actor)
+}
@@ -0,0 +1,188 @@
+package actor
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrFileChanged is returned by WriteToFile when the file an actor was parsed
+// from has been changed by something else since
+var ErrFileChanged = errors.New("File has changed since the actor was parsed from it")
+
+// fileSource is the state of the file an actor was parsed from
+type fileSource struct {
+	path    string
+	modTime time.Time
+	size    int64
+	hash    [sha256.Size]byte
+}
+
+func newFileSource(path string, info os.FileInfo, content []byte) *fileSource {
+	return &fileSource{path: canonicalPath(path), modTime: info.ModTime(), size: info.Size(), hash: sha256.Sum256(content)}
+}
+
+// changed reports whether the file at path is the source and no longer
+// holds what was parsed. A file that was only touched hasn't changed.
+func (s *fileSource) changed(path string, info os.FileInfo) (bool, error) {
+
+	if s == nil || info == nil {
+		return false, nil
+	}
+
+	if canonicalPath(path) != s.path {
+		return false, nil
+	}
+
+	if info.Size() != s.size {
+		return true, nil
+	}
+
+	if info.ModTime().Equal(s.modTime) {
+		return false, nil
+	}
+
+	content, err := ioutil.ReadFile(path)
+
+	if err != nil {
+		return false, err
+	}
+
+	return sha256.Sum256(content) != s.hash, nil
+}
+
+// canonicalPath is the absolute path of a file, after following symlinks
+func canonicalPath(path string) string {
+
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+
+	return path
+}
+
+// writeFile replaces the file at path with the content in one step, by
+// writing a temporary file alongside it and renaming that over it, so a
+// crash never leaves a partly written file. The file keeps its permissions,
+// and a new file is given perm.
+func writeFile(path string, content []byte, perm os.FileMode, backupSuffix string, source *fileSource, force bool) (*fileSource, error) {
+
+	// Writing through a symlink replaces the file it points at
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+
+	info, err := os.Stat(path)
+
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	mode := perm
+
+	if info != nil {
+		mode = info.Mode().Perm()
+	}
+
+	if !force {
+		if changed, err := source.changed(path, info); err != nil {
+			return nil, err
+		} else if changed {
+			return nil, ErrFileChanged
+		}
+	}
+
+	if info != nil && backupSuffix != "" {
+		if err := copyFile(path, path+backupSuffix, mode); err != nil {
+			return nil, err
+		}
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Nothing is left behind if any of the steps fail
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return nil, err
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return nil, err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return nil, err
+	}
+
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return nil, err
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return nil, err
+	}
+
+	info, err = os.Stat(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return newFileSource(path, info, content), nil
+}
+
+func copyFile(from, to string, mode os.FileMode) error {
+
+	content, err := ioutil.ReadFile(from)
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := writeFile(to, content, mode, "", nil, true); err != nil {
+		return err
+	}
+
+	return os.Chmod(to, mode)
+}
+
+// readFile reads a file, and the state it was read in
+func readFile(path string) (*bytes.Buffer, *fileSource, error) {
+
+	file, err := os.Open(path)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer file.Close()
+
+	info, err := file.Stat()
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	buf := bytes.NewBuffer(nil)
+
+	if _, err := buf.ReadFrom(file); err != nil {
+		return nil, nil, err
+	}
+
+	return buf, newFileSource(path, info, buf.Bytes()), nil
+}
@@ -0,0 +1,118 @@
+package actor
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func parseTestFile(t *testing.T, path string) *Actor {
+
+	parser, err := NewFileParser(path)
+	assert.Nil(t, err)
+
+	a, err := parser.Parse()
+	assert.Nil(t, err)
+
+	return a
+}
+
+func Test_WritingToAFileKeepsItsPermissions(t *testing.T) {
+
+	dir := t.TempDir()
+
+	name := filepath.Join(dir, "mock.actor")
+
+	assert.Nil(t, newMockActor().WriteToFile(name))
+
+	info, err := os.Stat(name)
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0644), info.Mode().Perm())
+
+	assert.Nil(t, os.Chmod(name, 0600))
+	assert.Nil(t, newMockActor().WriteToFile(name))
+
+	info, err = os.Stat(name)
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	// The temporary file has been renamed over the file
+	files, err := ioutil.ReadDir(dir)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(files))
+}
+
+func Test_WritingToAFileCanKeepABackup(t *testing.T) {
+
+	dir := t.TempDir()
+
+	name := filepath.Join(dir, "mock.actor")
+
+	assert.Nil(t, ioutil.WriteFile(name, []byte("Actor: Old\n"), 0640))
+	assert.Nil(t, newMockActor().WriteToFileWithOptions(name, WriteOptions{BackupSuffix: ".bak"}))
+
+	backup, err := ioutil.ReadFile(name + ".bak")
+	assert.Nil(t, err)
+	assert.Equal(t, "Actor: Old\n", string(backup))
+
+	info, err := os.Stat(name + ".bak")
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0640), info.Mode().Perm())
+
+	compareActors(t, newMockActor(), parseTestFile(t, name))
+}
+
+func Test_ItWontOverwriteAFileThatChangedSinceItWasParsed(t *testing.T) {
+
+	dir := t.TempDir()
+
+	name := filepath.Join(dir, "mock.actor")
+
+	assert.Nil(t, ioutil.WriteFile(name, []byte("Actor: Original\n"), 0644))
+
+	a := parseTestFile(t, name)
+	a.Name = "Mine"
+
+	// Only touching the file doesn't count as a change
+	later := time.Now().Add(time.Hour)
+	assert.Nil(t, os.Chtimes(name, later, later))
+	assert.Nil(t, a.WriteToFile(name))
+
+	// Writing it again is fine, as the actor now matches the file
+	assert.Nil(t, a.WriteToFile(name))
+
+	assert.Nil(t, ioutil.WriteFile(name, []byte("Actor: Theirs\n"), 0644))
+	assert.Equal(t, ErrFileChanged, a.WriteToFile(name))
+	assert.Equal(t, "Theirs", parseTestFile(t, name).Name)
+
+	// Other files, and forced writes, aren't checked
+	assert.Nil(t, a.WriteToFile(filepath.Join(dir, "other.actor")))
+	assert.Nil(t, a.WriteToFileWithOptions(name, WriteOptions{Force: true}))
+	assert.Equal(t, "Mine", parseTestFile(t, name).Name)
+}
+
+func Test_WritingThroughASymlinkReplacesItsTarget(t *testing.T) {
+
+	dir := t.TempDir()
+
+	target, link := filepath.Join(dir, "target.actor"), filepath.Join(dir, "link.actor")
+
+	assert.Nil(t, ioutil.WriteFile(target, []byte("Actor: Original\n"), 0644))
+	assert.Nil(t, os.Symlink(target, link))
+
+	a := parseTestFile(t, link)
+
+	assert.Nil(t, ioutil.WriteFile(target, []byte("Actor: Theirs\n"), 0644))
+	assert.Equal(t, ErrFileChanged, a.WriteToFile(target))
+
+	assert.Nil(t, a.WriteToFileWithOptions(link, WriteOptions{Force: true}))
+
+	info, err := os.Lstat(link)
+	assert.Nil(t, err)
+	assert.True(t, info.Mode()&os.ModeSymlink != 0)
+	assert.Equal(t, "Original", parseTestFile(t, target).Name)
+}
@@ -0,0 +1,214 @@
+package actor
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// WriteFS is a file system that actors can be written to as well as read
+// from. DirFS is one backed by a directory.
+type WriteFS interface {
+	fs.FS
+
+	// WriteFile writes a file, creating it with perm if it doesn't exist
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+}
+
+type dirFS struct {
+	fs.FS
+	dir string
+}
+
+// DirFS returns a WriteFS for the files under dir, which writes each file
+// atomically as WriteToFile does
+func DirFS(dir string) WriteFS {
+	return &dirFS{FS: os.DirFS(dir), dir: dir}
+}
+
+func (d *dirFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+
+	if !fs.ValidPath(name) {
+		return &fs.PathError{Op: "write", Path: name, Err: fs.ErrInvalid}
+	}
+
+	_, err := writeFile(filepath.Join(d.dir, filepath.FromSlash(name)), data, perm, "", nil, true)
+
+	return err
+}
+
+// NewFSParser parses a file from a file system, such as an embed.FS
+func NewFSParser(fsys fs.FS, name string) (Parser, error) {
+	return NewFSParserWithOptions(fsys, name, ParserOptions{})
+}
+
+func NewFSParserWithOptions(fsys fs.FS, name string, options ParserOptions) (Parser, error) {
+
+	content, err := fs.ReadFile(fsys, name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewParserWithOptions(bytes.NewBuffer(content), options), nil
+}
+
+// ReadTagRegistryFS reads a tag registry from a file system
+func ReadTagRegistryFS(fsys fs.FS, name string) (*TagRegistry, error) {
+	return readTagRegistryFS(fsys, name, name)
+}
+
+// readTagRegistryFS reads a tag registry, naming it by its path in errors
+func readTagRegistryFS(fsys fs.FS, name, path string) (*TagRegistry, error) {
+
+	file, err := fsys.Open(name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	registry, err := ReadTagRegistry(file)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err)
+	}
+
+	return registry, nil
+}
+
+// LoadProjectFS parses every .actor file under root in a file system, as
+// LoadProject does under a directory. The files' paths are slash separated,
+// as the file system names them.
+func LoadProjectFS(fsys fs.FS, root string, options ParserOptions) (*Project, error) {
+
+	load := func(name string, options ParserOptions) *ProjectFile {
+		return LoadProjectFileFS(fsys, name, options)
+	}
+
+	return loadProjectFS(fsys, root, options, func(name string) string { return name }, load)
+}
+
+// loadProjectFS finds the .actor files under root, and the tag registry, and
+// loads each file by the path that pathOf gives its name
+func loadProjectFS(fsys fs.FS, root string, options ParserOptions, pathOf func(name string) string, load func(path string, options ParserOptions) *ProjectFile) (*Project, error) {
+
+	project := &Project{Root: root, Files: make([]*ProjectFile, 0)}
+	paths := make([]string, 0)
+
+	if options.Tags == nil {
+
+		name := path.Join(root, DefaultTagRegistryFile)
+		registry, err := readTagRegistryFS(fsys, name, pathOf(name))
+
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+
+		options.Tags = registry
+	}
+
+	project.Tags = options.Tags
+
+	err := fs.WalkDir(fsys, root, func(name string, entry fs.DirEntry, err error) error {
+
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() && name != root && strings.HasPrefix(entry.Name(), ".") {
+			return fs.SkipDir
+		}
+
+		if !entry.IsDir() && path.Ext(name) == ".actor" {
+			paths = append(paths, name)
+		}
+
+		return nil
+	})
+
+	if pathErr, ok := err.(*fs.PathError); ok {
+		pathErr.Path = pathOf(pathErr.Path)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(paths)
+
+	for _, name := range paths {
+		project.Files = append(project.Files, load(pathOf(name), options))
+	}
+
+	return project, nil
+}
+
+// LoadProjectFileFS parses a single file from a file system
+func LoadProjectFileFS(fsys fs.FS, name string, options ParserOptions) *ProjectFile {
+
+	file := &ProjectFile{Path: name}
+
+	parser, err := NewFSParserWithOptions(fsys, name, options)
+
+	if err != nil {
+		file.Err = err
+		return file
+	}
+
+	file.Actor, file.Err = parser.Parse()
+	file.Diagnostics = parser.Diagnostics()
+
+	return file
+}
+
+// WriteToFS writes the actor to a file in a file system
+func (a *Actor) WriteToFS(fsys WriteFS, name string) error {
+	return a.WriteToFSWithOptions(fsys, name, WriteOptions{})
+}
+
+// WriteToFSWithOptions writes the actor to a file in a file system, keeping
+// a backup of the file if the options ask for one. Whether the file has
+// changed since it was parsed is only checked by WriteToFile.
+func (a *Actor) WriteToFSWithOptions(fsys WriteFS, name string, options WriteOptions) error {
+
+	buf := &bytes.Buffer{}
+
+	if err := a.WriteWithOptions(buf, options); err != nil {
+		return err
+	}
+
+	// The backup and the rewritten file keep the permissions of the file
+	info, err := fs.Stat(fsys, name)
+
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	perm := fs.FileMode(0644)
+
+	if info != nil {
+		perm = info.Mode().Perm()
+	}
+
+	if info != nil && options.BackupSuffix != "" {
+
+		old, err := fs.ReadFile(fsys, name)
+
+		if err != nil {
+			return err
+		}
+
+		if err := fsys.WriteFile(name+options.BackupSuffix, old, perm); err != nil {
+			return err
+		}
+	}
+
+	return fsys.WriteFile(name, buf.Bytes(), perm)
+}
@@ -0,0 +1,124 @@
+package actor
+
+import (
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// memFS is a writable fstest.MapFS
+type memFS struct {
+	fstest.MapFS
+}
+
+func (m memFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	m.MapFS[name] = &fstest.MapFile{Data: data, Mode: perm}
+	return nil
+}
+
+func Test_ItParsesAFileFromAFileSystem(t *testing.T) {
+
+	fsys := fstest.MapFS{"people/admin.actor": {Data: []byte("Actor: Admin\n    Goal: Moderate\n")}}
+
+	parser, err := NewFSParser(fsys, "people/admin.actor")
+	assert.Nil(t, err)
+
+	a, err := parser.Parse()
+	assert.Nil(t, err)
+	assert.Equal(t, "Admin", a.Name)
+
+	_, err = NewFSParser(fsys, "missing.actor")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func Test_ItLoadsAProjectFromAFileSystem(t *testing.T) {
+
+	fsys := fstest.MapFS{
+		"admin.actor":          {Data: []byte("@staff\nActor: Admin\n")},
+		"people/visitor.actor": {Data: []byte("Actor: Visitor\n")},
+		"people/broken.actor":  {Data: []byte("Actor:\n")},
+		".hidden/skip.actor":   {Data: []byte("Actor: Hidden\n")},
+		".actor-tags.json":     {Data: []byte(`{"tags": [{"name": "staff"}]}`)},
+	}
+
+	project, err := LoadProjectFS(fsys, ".", ParserOptions{})
+	assert.Nil(t, err)
+
+	if assert.Equal(t, 3, len(project.Files)) {
+		assert.Equal(t, "admin.actor", project.Files[0].Path)
+		assert.Equal(t, "people/broken.actor", project.Files[1].Path)
+		assert.NotNil(t, project.Files[1].Err)
+		assert.Equal(t, "Visitor", project.Files[2].Actor.Name)
+	}
+
+	assert.NotNil(t, project.Tags.Lookup("staff"))
+
+	fsys[".actor-tags.json"] = &fstest.MapFile{Data: []byte(`{"tags": [{"name": "other"}]}`)}
+
+	project, err = LoadProjectFS(fsys, ".", ParserOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, "[Line 0001:01] Unknown tag '@staff'", project.Files[0].Err.Error())
+}
+
+func Test_ItWritesAnActorToAFileSystem(t *testing.T) {
+
+	fsys := memFS{fstest.MapFS{"mock.actor": {Data: []byte("Actor: Old\n"), Mode: 0600}}}
+
+	assert.Nil(t, newMockActor().WriteToFSWithOptions(fsys, "mock.actor", WriteOptions{BackupSuffix: "~"}))
+	assert.Equal(t, "Actor: Old\n", string(fsys.MapFS["mock.actor~"].Data))
+
+	// The backup and the file keep the file's permissions
+	assert.Equal(t, fs.FileMode(0600), fsys.MapFS["mock.actor~"].Mode)
+	assert.Equal(t, fs.FileMode(0600), fsys.MapFS["mock.actor"].Mode)
+
+	assert.Nil(t, newMockActor().WriteToFS(fsys, "new.actor"))
+	assert.Equal(t, fs.FileMode(0644), fsys.MapFS["new.actor"].Mode)
+
+	parser, err := NewFSParser(fsys, "mock.actor")
+	assert.Nil(t, err)
+
+	a, err := parser.Parse()
+	assert.Nil(t, err)
+	compareActors(t, newMockActor(), a)
+
+	invalid := newMockActor()
+	invalid.Name = ""
+
+	assert.NotNil(t, invalid.WriteToFSWithOptions(fsys, "mock.actor", WriteOptions{Validate: true}))
+}
+
+func Test_DirFSWritesToADirectory(t *testing.T) {
+
+	dir := t.TempDir()
+
+	fsys := DirFS(dir)
+
+	assert.Nil(t, os.Mkdir(filepath.Join(dir, "people"), 0755))
+	assert.Nil(t, newMockActor().WriteToFS(fsys, "people/mock.actor"))
+
+	content, err := ioutil.ReadFile(filepath.Join(dir, "people", "mock.actor"))
+	assert.Nil(t, err)
+
+	read, err := fs.ReadFile(fsys, "people/mock.actor")
+	assert.Nil(t, err)
+	assert.Equal(t, content, read)
+
+	info, err := os.Stat(filepath.Join(dir, "people", "mock.actor"))
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0644), info.Mode().Perm())
+
+	// A backup of a private file is private too
+	assert.Nil(t, os.Chmod(filepath.Join(dir, "people", "mock.actor"), 0600))
+	assert.Nil(t, newMockActor().WriteToFSWithOptions(fsys, "people/mock.actor", WriteOptions{BackupSuffix: ".bak"}))
+
+	info, err = os.Stat(filepath.Join(dir, "people", "mock.actor.bak"))
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	assert.NotNil(t, fsys.WriteFile("../outside.actor", content, 0644))
+}
@@ -0,0 +1,231 @@
+// Package gen writes Go source declaring a typed constant for every actor,
+// goal and tag of a set of .actor files, so that code referring to them -
+// godog step definitions, for one - stops compiling when one is renamed,
+// rather than silently no longer matching.
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+
+	gherkin "github.com/cucumber/gherkin-go"
+	"github.com/dryvercorp/actor"
+)
+
+// Options changes the code Generate writes
+type Options struct {
+	// Package is the name of the generated package, and defaults to actors
+	Package string
+
+	// Generator names the command in the generated file's header
+	Generator string
+}
+
+const defaultPackage = "actors"
+
+// Identifiers the generated code declares itself
+var reserved = map[string]bool{
+	"Actor": true, "Actors": true, "ActorName": true, "Goal": true, "GoalName": true, "TagName": true,
+}
+
+type constant struct {
+	Identifier string
+	Value      string
+	Comment    string
+}
+
+type goal struct {
+	Name constant
+	Tags []string
+}
+
+type actorData struct {
+	Name  constant
+	Path  string
+	Tags  []string
+	Goals []*goal
+}
+
+type data struct {
+	Generator string
+	Package   string
+	Actors    []*actorData
+	Tags      []constant
+}
+
+// generator names the identifiers, making sure no two are the same
+type generator struct {
+	names map[string]string
+	tags  map[string]string
+}
+
+// Generate writes the Go source for the actors of a project, which must all
+// have parsed
+func Generate(w io.Writer, project *actor.Project, options Options) error {
+
+	if options.Package == "" {
+		options.Package = defaultPackage
+	}
+
+	if !token.IsIdentifier(options.Package) {
+		return fmt.Errorf("Package name '%s' is not a valid identifier", options.Package)
+	}
+
+	if options.Generator == "" {
+		options.Generator = "actorgen"
+	}
+
+	g := &generator{names: make(map[string]string), tags: make(map[string]string)}
+	d := &data{Generator: options.Generator, Package: options.Package}
+
+	for _, file := range project.Files {
+
+		if file.Err != nil {
+			return fmt.Errorf("%s: %s", file.Path, file.Err)
+		}
+
+		if file.Actor == nil {
+			continue
+		}
+
+		a, err := g.actor(file.Path, file.Actor)
+
+		if err != nil {
+			return fmt.Errorf("%s: %s", file.Path, err)
+		}
+
+		d.Actors = append(d.Actors, a)
+	}
+
+	for name, identifier := range g.tags {
+		d.Tags = append(d.Tags, constant{Identifier: identifier, Value: name})
+	}
+
+	sort.Slice(d.Tags, func(i, j int) bool { return d.Tags[i].Identifier < d.Tags[j].Identifier })
+
+	buf := &bytes.Buffer{}
+
+	if err := source.Execute(buf, d); err != nil {
+		return err
+	}
+
+	formatted, err := format.Source(buf.Bytes())
+
+	if err != nil {
+		return fmt.Errorf("Generated code doesn't compile: %s", err)
+	}
+
+	_, err = w.Write(formatted)
+
+	return err
+}
+
+func (g *generator) actor(path string, a *actor.Actor) (*actorData, error) {
+
+	id, err := g.name(identifier(a.Name), fmt.Sprintf("actor '%s'", a.Name))
+
+	if err != nil {
+		return nil, err
+	}
+
+	d := &actorData{
+		Name: constant{Identifier: id, Value: a.Name, Comment: fmt.Sprintf("%s is defined in %s", id, path)},
+		Path: path,
+	}
+
+	if d.Tags, err = g.tagList(a.Tags); err != nil {
+		return nil, err
+	}
+
+	for _, goalDef := range a.Goals {
+
+		goalIdentifier, err := g.name(id+identifierOf(goalDef.Name), fmt.Sprintf("goal '%s' of actor '%s'", goalDef.Name, a.Name))
+
+		if err != nil {
+			return nil, err
+		}
+
+		tags, err := g.tagList(goalDef.Tags)
+
+		if err != nil {
+			return nil, err
+		}
+
+		d.Goals = append(d.Goals, &goal{Name: constant{Identifier: goalIdentifier, Value: goalDef.Name}, Tags: tags})
+	}
+
+	return d, nil
+}
+
+// name claims an identifier for what's described
+func (g *generator) name(identifier, description string) (string, error) {
+
+	if reserved[identifier] {
+		return "", fmt.Errorf("The %s would be named %s, which is reserved", description, identifier)
+	}
+
+	if other, ok := g.names[identifier]; ok {
+		return "", fmt.Errorf("The %s and the %s would both be named %s", other, description, identifier)
+	}
+
+	g.names[identifier] = description
+
+	return identifier, nil
+}
+
+func (g *generator) tagList(tags []*gherkin.Tag) ([]string, error) {
+
+	identifiers := make([]string, 0, len(tags))
+
+	for _, tag := range tags {
+
+		identifier, ok := g.tags[tag.Name]
+
+		if !ok {
+			var err error
+
+			if identifier, err = g.name("Tag"+identifierOf(tag.Name), fmt.Sprintf("tag '@%s'", tag.Name)); err != nil {
+				return nil, err
+			}
+
+			g.tags[tag.Name] = identifier
+		}
+
+		identifiers = append(identifiers, identifier)
+	}
+
+	return identifiers, nil
+}
+
+// identifier makes an exported Go identifier of a name
+func identifier(name string) string {
+
+	id := identifierOf(name)
+
+	if r := []rune(id); len(r) == 0 || !unicode.IsUpper(r[0]) {
+		id = "X" + id
+	}
+
+	return id
+}
+
+// identifierOf joins the words of a name, each starting with a capital
+func identifierOf(name string) string {
+
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for i, word := range words {
+		r := []rune(word)
+		words[i] = string(unicode.ToUpper(r[0])) + string(r[1:])
+	}
+
+	return strings.Join(words, "")
+}
@@ -0,0 +1,127 @@
+package gen
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+	"testing/fstest"
+
+	"github.com/dryvercorp/actor"
+	"github.com/stretchr/testify/assert"
+)
+
+func loadTestProject(t *testing.T, files map[string]string) *actor.Project {
+
+	fsys := fstest.MapFS{}
+
+	for name, content := range files {
+		fsys[name] = &fstest.MapFile{Data: []byte(content)}
+	}
+
+	project, err := actor.LoadProjectFS(fsys, ".", actor.ParserOptions{})
+	assert.Nil(t, err)
+
+	return project
+}
+
+// typeCheck compiles the generated source
+func typeCheck(t *testing.T, source []byte) *types.Package {
+
+	files := token.NewFileSet()
+	file, err := parser.ParseFile(files, "actors_gen.go", source, parser.ParseComments)
+
+	if !assert.Nil(t, err) {
+		return nil
+	}
+
+	pkg, err := (&types.Config{}).Check(file.Name.Name, files, []*ast.File{file}, nil)
+	assert.Nil(t, err)
+
+	return pkg
+}
+
+func Test_ItGeneratesConstantsForActorsGoalsAndTags(t *testing.T) {
+
+	project := loadTestProject(t, map[string]string{
+		"admin.actor": "@staff\nActor: Site administrator\n    @moderation\n    Goal: Moderate \"spam\" comments\n    Goals:\n        Publish articles\n",
+		"guest.actor": "Actor: guest\n",
+		"line.actor":  "Actor: 2nd line support\n",
+	})
+
+	buf := &bytes.Buffer{}
+
+	assert.Nil(t, Generate(buf, project, Options{Package: "people"}))
+
+	source := buf.String()
+
+	assert.Contains(t, source, "// Code generated by actorgen. DO NOT EDIT.\n\npackage people\n")
+	assert.Contains(t, source, "// SiteAdministrator is defined in admin.actor\nconst SiteAdministrator ActorName = \"Site administrator\"\n")
+	assert.Contains(t, source, "\tSiteAdministratorModerateSpamComments GoalName = \"Moderate \\\"spam\\\" comments\"\n")
+	assert.Contains(t, source, "\tSiteAdministratorPublishArticles      GoalName = \"Publish articles\"\n")
+	assert.Contains(t, source, "const Guest ActorName = \"guest\"\n")
+	assert.Contains(t, source, "const X2ndLineSupport ActorName = \"2nd line support\"\n")
+	assert.Contains(t, source, "\t\tName: Guest,\n\t\tPath: \"guest.actor\",\n\t},\n")
+	assert.Contains(t, source, "\tTagModeration TagName = \"moderation\"\n")
+
+	pkg := typeCheck(t, buf.Bytes())
+
+	if pkg != nil {
+		assert.Equal(t, "people", pkg.Name())
+		assert.NotNil(t, pkg.Scope().Lookup("SiteAdministratorPublishArticles"))
+		assert.NotNil(t, pkg.Scope().Lookup("TagStaff"))
+		assert.NotNil(t, pkg.Scope().Lookup("Actors"))
+	}
+}
+
+func Test_ItGeneratesAPackageWithNoActors(t *testing.T) {
+
+	buf := &bytes.Buffer{}
+
+	assert.Nil(t, Generate(buf, &actor.Project{}, Options{}))
+	assert.Contains(t, buf.String(), "package actors\n")
+
+	typeCheck(t, buf.Bytes())
+}
+
+func Test_ItRefusesToGenerateAmbiguousCode(t *testing.T) {
+
+	for _, test := range []struct {
+		files    map[string]string
+		options  Options
+		expected string
+	}{
+		{
+			files:    map[string]string{"a.actor": "Actor: Admin\n", "b.actor": "Actor: admin!\n"},
+			expected: "b.actor: The actor 'Admin' and the actor 'admin!' would both be named Admin",
+		},
+		{
+			files:    map[string]string{"a.actor": "Actor: Admin\n    Goal: Moderate\n    Goal: moderate?\n"},
+			expected: "a.actor: The goal 'Moderate' of actor 'Admin' and the goal 'moderate?' of actor 'Admin' would both be named AdminModerate",
+		},
+		{
+			files:    map[string]string{"a.actor": "@wip-1 @wip_1\nActor: Admin\n"},
+			expected: "a.actor: The tag '@wip-1' and the tag '@wip_1' would both be named TagWip1",
+		},
+		{
+			files:    map[string]string{"a.actor": "Actor: Goal\n"},
+			expected: "a.actor: The actor 'Goal' would be named Goal, which is reserved",
+		},
+		{
+			files:    map[string]string{"a.actor": "Actor:\n"},
+			expected: "a.actor: [Line 0001:01] Actor keyword must be followed by an actor name",
+		},
+		{
+			options:  Options{Package: "not valid"},
+			expected: "Package name 'not valid' is not a valid identifier",
+		},
+	} {
+		err := Generate(&bytes.Buffer{}, loadTestProject(t, test.files), test.options)
+
+		if assert.NotNil(t, err) {
+			assert.Equal(t, test.expected, err.Error())
+		}
+	}
+}
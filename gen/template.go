@@ -0,0 +1,66 @@
+package gen
+
+import "text/template"
+
+var source = template.Must(template.New("source").Parse(`// Code generated by {{.Generator}}. DO NOT EDIT.
+
+package {{.Package}}
+
+// ActorName is the name of an actor
+type ActorName string
+
+// GoalName is the name of one of an actor's goals
+type GoalName string
+
+// TagName is a tag, without its @
+type TagName string
+
+// Actor is an actor as defined in its .actor file
+type Actor struct {
+	Name  ActorName
+	Path  string
+	Tags  []TagName
+	Goals []Goal
+}
+
+type Goal struct {
+	Name GoalName
+	Tags []TagName
+}
+{{range .Actors}}
+// {{.Name.Comment}}
+const {{.Name.Identifier}} ActorName = {{printf "%q" .Name.Value}}
+{{if .Goals}}
+const (
+{{- range .Goals}}
+	{{.Name.Identifier}} GoalName = {{printf "%q" .Name.Value}}
+{{- end}}
+)
+{{end}}{{end}}
+{{- if .Tags}}
+const (
+{{- range .Tags}}
+	{{.Identifier}} TagName = {{printf "%q" .Value}}
+{{- end}}
+)
+{{end}}
+// Actors is every actor, in the order of their files
+var Actors = []Actor{
+{{- range .Actors}}
+	{
+		Name: {{.Name.Identifier}},
+		Path: {{printf "%q" .Path}},
+		{{- if .Tags}}
+		Tags: []TagName{ {{- template "tags" .Tags}}},
+		{{- end}}
+		{{- if .Goals}}
+		Goals: []Goal{
+		{{- range .Goals}}
+			{Name: {{.Name.Identifier}}{{if .Tags}}, Tags: []TagName{ {{- template "tags" .Tags}}}{{end}}},
+		{{- end}}
+		},
+		{{- end}}
+	},
+{{- end}}
+}
+{{define "tags"}}{{range $i, $tag := .}}{{if $i}}, {{end}}{{$tag}}{{end}}{{end}}`))
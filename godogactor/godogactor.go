@@ -0,0 +1,264 @@
+// Package godogactor adds steps to godog scenarios that name the actor, and
+// the goal, that a scenario is about, checked against the project's .actor
+// files:
+//
+//	Scenario: Removing spam
+//	    Given I am a "Site administrator"
+//	    And I want to "Moderate comments"
+//
+// A scenario, or its feature, may instead be tagged with the actor's name or
+// one of its aliases, such as @site-admin. Later steps find the actor and
+// goal with ActorFrom and GoalFrom, and the goals the scenarios exercised are
+// kept for a coverage report.
+package godogactor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/cucumber/godog"
+	"github.com/dryvercorp/actor"
+)
+
+// Steps registers the actor steps with godog, and records which goals the
+// scenarios exercise
+type Steps struct {
+	project *actor.Project
+
+	mu        sync.Mutex
+	exercised map[*actor.Goal][]string
+}
+
+// GoalCoverage is a goal and the scenarios that exercised it
+type GoalCoverage struct {
+	Path      string
+	Actor     *actor.Actor
+	Goal      *actor.Goal
+	Scenarios []string // Each as the feature file's URI and the scenario's name
+}
+
+// scenario is what the steps of a scenario have chosen so far
+type scenario struct {
+	name  string
+	actor *actor.Actor
+	goal  *actor.Goal
+	goals []*actor.Goal
+}
+
+type scenarioKey struct{}
+
+// New returns the steps for the actors of a project
+func New(project *actor.Project) *Steps {
+	return &Steps{project: project, exercised: make(map[*actor.Goal][]string)}
+}
+
+// Load parses the project under root, and fails if any of its files don't
+// parse, as the steps would otherwise report their actors as unknown
+func Load(root string, options actor.ParserOptions) (*Steps, error) {
+
+	project, err := actor.LoadProject(root, options)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range project.Files {
+		if file.Err != nil {
+			return nil, fmt.Errorf("%s: %s", file.Path, file.Err)
+		}
+	}
+
+	return New(project), nil
+}
+
+// InitializeScenario registers the steps, and is called from a godog
+// ScenarioInitializer
+func (s *Steps) InitializeScenario(sc *godog.ScenarioContext) {
+
+	sc.Before(func(ctx context.Context, pickle *godog.Scenario) (context.Context, error) {
+
+		state := &scenario{name: fmt.Sprintf("%s: %s", pickle.Uri, pickle.Name)}
+
+		// A tag such as @site-admin names the actor without a step
+		for _, tag := range pickle.Tags {
+			if file := s.project.FindActorByTag(tag.Name); file != nil {
+				state.actor = file.Actor
+				break
+			}
+		}
+
+		return context.WithValue(ctx, scenarioKey{}, state), nil
+	})
+
+	sc.After(func(ctx context.Context, pickle *godog.Scenario, err error) (context.Context, error) {
+
+		if state, ok := ctx.Value(scenarioKey{}).(*scenario); ok {
+			s.record(state)
+		}
+
+		return ctx, nil
+	})
+
+	sc.Step(`^I am an? "([^"]*)"$`, s.iAm)
+	sc.Step(`^(?i:as) an? "?([^",]*?)"?,?$`, s.iAm)
+	sc.Step(`^I want to "([^"]*)"$`, s.iWantTo)
+	sc.Step(`^my goal is "([^"]*)"$`, s.iWantTo)
+}
+
+func (s *Steps) iAm(ctx context.Context, name string) (context.Context, error) {
+
+	state, err := scenarioFrom(ctx)
+
+	if err != nil {
+		return ctx, err
+	}
+
+	file := s.project.FindActor(name)
+
+	if file == nil {
+		return ctx, fmt.Errorf("Unknown actor '%s'", name)
+	}
+
+	state.actor, state.goal = file.Actor, nil
+
+	return ctx, nil
+}
+
+func (s *Steps) iWantTo(ctx context.Context, name string) (context.Context, error) {
+
+	state, err := scenarioFrom(ctx)
+
+	if err != nil {
+		return ctx, err
+	}
+
+	if state.actor == nil {
+		return ctx, fmt.Errorf("Goal '%s' needs an actor: start with 'I am a \"...\"'", name)
+	}
+
+	goal := state.actor.FindGoal(name)
+
+	if goal == nil {
+		return ctx, fmt.Errorf("Actor '%s' has no goal '%s'", state.actor.Name, name)
+	}
+
+	state.goal = goal
+	state.goals = append(state.goals, goal)
+
+	return ctx, nil
+}
+
+func scenarioFrom(ctx context.Context) (*scenario, error) {
+
+	state, ok := ctx.Value(scenarioKey{}).(*scenario)
+
+	if !ok {
+		return nil, fmt.Errorf("The actor steps weren't registered with InitializeScenario")
+	}
+
+	return state, nil
+}
+
+// ActorFrom returns the actor a scenario's steps have named so far, or nil
+func ActorFrom(ctx context.Context) *actor.Actor {
+
+	if state, ok := ctx.Value(scenarioKey{}).(*scenario); ok {
+		return state.actor
+	}
+
+	return nil
+}
+
+// GoalFrom returns the goal a scenario's steps have named most recently, or
+// nil
+func GoalFrom(ctx context.Context) *actor.Goal {
+
+	if state, ok := ctx.Value(scenarioKey{}).(*scenario); ok {
+		return state.goal
+	}
+
+	return nil
+}
+
+func (s *Steps) record(state *scenario) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seen := make(map[*actor.Goal]bool)
+
+	for _, goal := range state.goals {
+		if !seen[goal] {
+			s.exercised[goal] = append(s.exercised[goal], state.name)
+			seen[goal] = true
+		}
+	}
+}
+
+// Coverage returns every goal of the project, in order, with the scenarios
+// that have exercised it so far
+func (s *Steps) Coverage() []*GoalCoverage {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	coverage := make([]*GoalCoverage, 0)
+
+	for _, file := range s.project.Files {
+
+		if file.Actor == nil {
+			continue
+		}
+
+		for _, goal := range file.Actor.Goals {
+			coverage = append(coverage, &GoalCoverage{
+				Path:      file.Path,
+				Actor:     file.Actor,
+				Goal:      goal,
+				Scenarios: append([]string(nil), s.exercised[goal]...),
+			})
+		}
+	}
+
+	return coverage
+}
+
+// WriteCoverage writes a line for each goal with how many scenarios
+// exercised it, and a total
+func (s *Steps) WriteCoverage(w io.Writer) error {
+
+	coverage := s.Coverage()
+	covered := 0
+
+	for _, c := range coverage {
+
+		if len(c.Scenarios) > 0 {
+			covered++
+		}
+
+		if _, err := fmt.Fprintf(w, "%s: %s: %s: %s\n", c.Path, c.Actor.Name, c.Goal.Name, plural(len(c.Scenarios), "scenario")); err != nil {
+			return err
+		}
+	}
+
+	percent := 100.0
+
+	if len(coverage) > 0 {
+		percent = float64(covered) * 100 / float64(len(coverage))
+	}
+
+	_, err := fmt.Fprintf(w, "%d of %d goals exercised (%.1f%%)\n", covered, len(coverage), percent)
+
+	return err
+}
+
+func plural(n int, noun string) string {
+
+	if n == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+
+	return fmt.Sprintf("%d %ss", n, noun)
+}
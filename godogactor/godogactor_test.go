@@ -0,0 +1,161 @@
+package godogactor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/cucumber/godog"
+	"github.com/dryvercorp/actor"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestSteps(t *testing.T) (*Steps, string) {
+
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"admin.actor":   "Actor: Site administrator\n    Aliases:\n        Admin\n        Site admin\n    Goals:\n        Moderate comments\n        Publish articles\n",
+		"visitor.actor": "Actor: Visitor\n    Goal: Read articles\n",
+	}
+
+	for name, content := range files {
+		assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+
+	steps, err := Load(dir, actor.ParserOptions{})
+	assert.Nil(t, err)
+
+	return steps, dir
+}
+
+// runFeature runs a feature with the actor steps, and a step that reports
+// the chosen actor and goal
+func runFeature(steps *Steps, feature string) (int, string, []string) {
+
+	output := &bytes.Buffer{}
+	chosen := make([]string, 0)
+
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(sc *godog.ScenarioContext) {
+
+			steps.InitializeScenario(sc)
+
+			sc.Step(`^it works$`, func(ctx context.Context) error {
+				chosen = append(chosen, fmt.Sprintf("%s/%s", ActorFrom(ctx).Name, GoalFrom(ctx).Name))
+				return nil
+			})
+		},
+		Options: &godog.Options{
+			Format:          "progress",
+			Output:          output,
+			Strict:          true,
+			NoColors:        true,
+			FeatureContents: []godog.Feature{{Name: "test.feature", Contents: []byte(feature)}},
+		},
+	}
+
+	return suite.Run(), output.String(), chosen
+}
+
+func Test_StepsNameTheActorAndGoalOfAScenario(t *testing.T) {
+
+	steps, dir := newTestSteps(t)
+
+	status, output, chosen := runFeature(steps, `Feature: Moderation
+
+  Scenario: Removing spam
+    Given I am a "site administrator"
+    And I want to "Moderate comments"
+    Then it works
+
+  Scenario: Reading
+    Given as a Visitor,
+    And my goal is "read articles"
+    Then it works
+
+  Scenario: Spam again
+    Given I am a "Site administrator"
+    And I want to "Moderate comments"
+    And I want to "Moderate comments"
+`)
+
+	assert.Equal(t, 0, status, output)
+	assert.Equal(t, []string{"Site administrator/Moderate comments", "Visitor/Read articles"}, chosen)
+
+	coverage := steps.Coverage()
+
+	if assert.Equal(t, 3, len(coverage)) {
+		assert.Equal(t, "Moderate comments", coverage[0].Goal.Name)
+		assert.Equal(t, []string{"test.feature: Removing spam", "test.feature: Spam again"}, coverage[0].Scenarios)
+		assert.Equal(t, 0, len(coverage[1].Scenarios))
+		assert.Equal(t, []string{"test.feature: Reading"}, coverage[2].Scenarios)
+	}
+
+	buf := &bytes.Buffer{}
+	assert.Nil(t, steps.WriteCoverage(buf))
+
+	assert.Equal(t, filepath.Join(dir, "admin.actor")+": Site administrator: Moderate comments: 2 scenarios\n"+
+		filepath.Join(dir, "admin.actor")+": Site administrator: Publish articles: 0 scenarios\n"+
+		filepath.Join(dir, "visitor.actor")+": Visitor: Read articles: 1 scenario\n"+
+		"2 of 3 goals exercised (66.7%)\n", buf.String())
+}
+
+func Test_AScenarioNamesItsActorByAliasOrTag(t *testing.T) {
+
+	steps, _ := newTestSteps(t)
+
+	status, output, chosen := runFeature(steps, `Feature: Publishing
+
+  Scenario: By alias
+    Given I am an "ADMIN"
+    And I want to "Publish articles"
+    Then it works
+
+  @site-admin
+  Scenario: By tag
+    Given I want to "Moderate comments"
+    Then it works
+`)
+
+	assert.Equal(t, 0, status, output)
+	assert.Equal(t, []string{"Site administrator/Publish articles", "Site administrator/Moderate comments"}, chosen)
+}
+
+func Test_StepsFailForUnknownActorsAndGoals(t *testing.T) {
+
+	steps, _ := newTestSteps(t)
+
+	for _, test := range []struct {
+		scenario string
+		expected string
+	}{
+		{`Given I am a "Robot"`, "Unknown actor 'Robot'"},
+		{`Given I want to "Publish articles"`, `Goal 'Publish articles' needs an actor: start with 'I am a "..."'`},
+		{"Given I am a \"Visitor\"\n    And I want to \"Publish articles\"", "Actor 'Visitor' has no goal 'Publish articles'"},
+	} {
+		status, output, _ := runFeature(steps, "Feature: F\n\n  Scenario: S\n    "+test.scenario+"\n")
+
+		assert.Equal(t, 1, status)
+		assert.Contains(t, output, test.expected)
+	}
+
+	assert.Nil(t, ActorFrom(context.Background()))
+	assert.Nil(t, GoalFrom(context.Background()))
+}
+
+func Test_LoadFailsIfAnActorDoesntParse(t *testing.T) {
+
+	dir := t.TempDir()
+
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "broken.actor"), []byte("Actor:\n"), 0644))
+
+	_, err := Load(dir, actor.ParserOptions{})
+
+	if assert.NotNil(t, err) {
+		assert.Equal(t, filepath.Join(dir, "broken.actor")+": [Line 0001:01] Actor keyword must be followed by an actor name", err.Error())
+	}
+}
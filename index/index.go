@@ -0,0 +1,237 @@
+// Package index answers questions across the actors of a project - which
+// goals mention refunds, which carry @mobile - from an index built in memory
+// once the project is loaded.
+package index
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	gherkin "github.com/cucumber/gherkin-go"
+	"github.com/dryvercorp/actor"
+)
+
+// Kind is the part of an actor a result is
+type Kind string
+
+const (
+	KindActor Kind = "actor" // The actor's name, or one of its aliases
+	KindBlurb Kind = "blurb"
+	KindGoal  Kind = "goal"
+)
+
+// Result is a part of an actor that matched. Blurb lines have no location.
+type Result struct {
+	Path     string            `json:"path"`
+	Actor    string            `json:"actor"`
+	Goal     string            `json:"goal,omitempty"`
+	Kind     Kind              `json:"kind"`
+	Text     string            `json:"text"`
+	Tags     []string          `json:"tags,omitempty"`
+	Location *gherkin.Location `json:"location,omitempty"`
+}
+
+func (r *Result) String() string {
+
+	prefix := r.Path
+
+	if r.Location != nil {
+		prefix = fmt.Sprintf("%s:%d:%d", r.Path, r.Location.Line, r.Location.Column)
+	}
+
+	switch {
+	case r.Kind == KindActor && r.Text != r.Actor:
+		return fmt.Sprintf("%s: %s (alias '%s')", prefix, r.Actor, r.Text)
+
+	case r.Kind == KindActor:
+		return fmt.Sprintf("%s: %s", prefix, r.Actor)
+	}
+
+	return fmt.Sprintf("%s: %s: %s", prefix, r.Actor, r.Text)
+}
+
+// Index is the words and tags of every actor of a project
+type Index struct {
+	results  []*Result
+	words    []string         // Every word of every result, sorted
+	postings map[string][]int // The results containing each word, in order
+	tagged   map[string][]int // The actors and goals carrying each tag, in order
+}
+
+// New indexes the actors of a project, skipping files that didn't parse
+func New(project *actor.Project) *Index {
+
+	idx := &Index{postings: make(map[string][]int), tagged: make(map[string][]int)}
+
+	for _, file := range project.Files {
+
+		a := file.Actor
+
+		if a == nil {
+			continue
+		}
+
+		idx.add(&Result{Path: file.Path, Actor: a.Name, Kind: KindActor, Text: a.Name, Tags: tagNames(a.Tags), Location: a.NameLocation})
+
+		for _, alias := range a.Aliases {
+			idx.add(&Result{Path: file.Path, Actor: a.Name, Kind: KindActor, Text: alias.Name, Location: alias.Location})
+		}
+
+		for _, blurb := range a.Blurb {
+			idx.add(&Result{Path: file.Path, Actor: a.Name, Kind: KindBlurb, Text: blurb})
+		}
+
+		for _, goal := range a.Goals {
+			idx.add(&Result{Path: file.Path, Actor: a.Name, Goal: goal.Name, Kind: KindGoal, Text: goal.Name, Tags: tagNames(goal.Tags), Location: goal.NameLocation})
+		}
+	}
+
+	for word := range idx.postings {
+		idx.words = append(idx.words, word)
+	}
+
+	sort.Strings(idx.words)
+
+	return idx
+}
+
+func (idx *Index) add(r *Result) {
+
+	i := len(idx.results)
+	idx.results = append(idx.results, r)
+
+	for _, word := range words(r.Text) {
+		if postings := idx.postings[word]; len(postings) == 0 || postings[len(postings)-1] != i {
+			idx.postings[word] = append(postings, i)
+		}
+	}
+
+	for _, tag := range r.Tags {
+		if postings := idx.tagged[tag]; len(postings) == 0 || postings[len(postings)-1] != i {
+			idx.tagged[tag] = append(postings, i)
+		}
+	}
+}
+
+// Search returns the names, aliases, blurb lines and goals containing every
+// word of the query, in file order. Words match ignoring case, and as the
+// start of a longer word, so "refund" finds "Refunds". A word starting with @
+// is a tag that the actor or goal must carry itself.
+func (idx *Index) Search(query string) []*Result {
+
+	var matched []int
+	searched := false
+
+	for _, term := range strings.Fields(query) {
+
+		if strings.HasPrefix(term, "@") {
+			matched, searched = intersect(matched, idx.tagged[term[1:]], searched), true
+			continue
+		}
+
+		// A term such as "self-service" is searched as both its words
+		for _, word := range words(term) {
+			matched, searched = intersect(matched, idx.prefixed(word), searched), true
+		}
+	}
+
+	return idx.collect(matched)
+}
+
+// Tagged returns the actors and goals that carry a tag, with or without its
+// @, in file order
+func (idx *Index) Tagged(tag string) []*Result {
+	return idx.collect(idx.tagged[strings.TrimPrefix(tag, "@")])
+}
+
+// Tags returns every tag of the project's actors and goals, sorted
+func (idx *Index) Tags() []string {
+
+	tags := make([]string, 0, len(idx.tagged))
+
+	for tag := range idx.tagged {
+		tags = append(tags, tag)
+	}
+
+	sort.Strings(tags)
+
+	return tags
+}
+
+// prefixed returns the results containing a word that starts with prefix
+func (idx *Index) prefixed(prefix string) []int {
+
+	set := make(map[int]bool)
+
+	for i := sort.SearchStrings(idx.words, prefix); i < len(idx.words) && strings.HasPrefix(idx.words[i], prefix); i++ {
+		for _, r := range idx.postings[idx.words[i]] {
+			set[r] = true
+		}
+	}
+
+	found := make([]int, 0, len(set))
+
+	for r := range set {
+		found = append(found, r)
+	}
+
+	sort.Ints(found)
+
+	return found
+}
+
+func (idx *Index) collect(matched []int) []*Result {
+
+	results := make([]*Result, 0, len(matched))
+
+	for _, i := range matched {
+		results = append(results, idx.results[i])
+	}
+
+	return results
+}
+
+// intersect keeps the results found by a term, or all of them if it's the
+// first term searched
+func intersect(matched, found []int, searched bool) []int {
+
+	if !searched {
+		return found
+	}
+
+	both := make([]int, 0)
+
+	for i, j := 0, 0; i < len(matched) && j < len(found); {
+		switch {
+		case matched[i] < found[j]:
+			i++
+		case matched[i] > found[j]:
+			j++
+		default:
+			both = append(both, matched[i])
+			i, j = i+1, j+1
+		}
+	}
+
+	return both
+}
+
+// words splits text into lower case words of letters and digits
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func tagNames(tags []*gherkin.Tag) []string {
+
+	names := make([]string, 0, len(tags))
+
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+
+	return names
+}
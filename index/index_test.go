@@ -0,0 +1,102 @@
+package index
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dryvercorp/actor"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestIndex(t *testing.T) *Index {
+
+	parse := func(path, content string) *actor.ProjectFile {
+		a, err := actor.NewParser(bytes.NewBufferString(content)).Parse()
+		assert.Nil(t, err)
+		return &actor.ProjectFile{Path: path, Actor: a}
+	}
+
+	return New(&actor.Project{Files: []*actor.ProjectFile{
+		parse("support.actor", `@staff
+Actor: Support agent
+    Aliases:
+        Customer service
+    Handles refund requests
+
+    @mobile
+    Goal: Refund an order
+
+    Goals:
+        Answer tickets
+`),
+		{Path: "broken.actor"},
+		parse("shopper.actor", `Actor: Shopper
+    @mobile @checkout
+    Goal: Request a refund
+    Goal: Self-service returns
+`),
+	}})
+}
+
+func texts(results []*Result) []string {
+
+	found := make([]string, 0, len(results))
+
+	for _, r := range results {
+		found = append(found, r.String())
+	}
+
+	return found
+}
+
+func Test_ItSearchesNamesBlurbAndGoals(t *testing.T) {
+
+	idx := newTestIndex(t)
+
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{"refund", []string{
+			"support.actor: Support agent: Handles refund requests",
+			"support.actor:8:11: Support agent: Refund an order",
+			"shopper.actor:3:11: Shopper: Request a refund",
+		}},
+		{"REF req", []string{
+			"support.actor: Support agent: Handles refund requests",
+			"shopper.actor:3:11: Shopper: Request a refund",
+		}},
+		{"customer", []string{"support.actor:4:9: Support agent (alias 'Customer service')"}},
+		{"shopper", []string{"shopper.actor:1:8: Shopper"}},
+		{"self-serv", []string{"shopper.actor:4:11: Shopper: Self-service returns"}},
+		{"refund @mobile", []string{
+			"support.actor:8:11: Support agent: Refund an order",
+			"shopper.actor:3:11: Shopper: Request a refund",
+		}},
+		{"@staff", []string{"support.actor:2:8: Support agent"}},
+		{"refund @checkout order", []string{}},
+		{"  ", []string{}},
+		{"nothing", []string{}},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, texts(idx.Search(test.query)), test.query)
+	}
+}
+
+func Test_ItLooksUpTags(t *testing.T) {
+
+	idx := newTestIndex(t)
+
+	assert.Equal(t, []string{"checkout", "mobile", "staff"}, idx.Tags())
+
+	results := idx.Tagged("@mobile")
+
+	if assert.Equal(t, 2, len(results)) {
+		assert.Equal(t, &Result{Path: "support.actor", Actor: "Support agent", Goal: "Refund an order", Kind: KindGoal, Text: "Refund an order", Tags: []string{"mobile"}, Location: results[0].Location}, results[0])
+		assert.Equal(t, "Request a refund", results[1].Goal)
+		assert.Equal(t, []string{"mobile", "checkout"}, results[1].Tags)
+	}
+
+	assert.Equal(t, 0, len(idx.Tagged("missing")))
+}
@@ -2,8 +2,11 @@ package actor
 
 import (
 	"bufio"
+	"fmt"
 	"io"
 	"strings"
+
+	gherkin "github.com/cucumber/gherkin-go"
 )
 
 type lineContent string
@@ -13,32 +16,90 @@ type line struct {
 	line     int
 	column   int
 	content  lineContent
+	end      int // The last line, which is the closing delimiter of a doc string
 	children lexerTree
+	verbatim []string // Raw lines of a doc string, without the delimiters
+
+	// The tokens of the line, kept by the tokeniser that made them
+	tokens      []token
+	tokenisedBy tokeniser
 }
 
+const defaultTabWidth = 4
+
 type lexer struct {
-	reader io.Reader
-	lines  lexerTree
+	reader      io.Reader
+	lines       lexerTree
+	strict      bool
+	tabWidth    int
+	diagnostics []*Diagnostic
+
+	// Subtrees of an earlier lex that can be reused, and where the
+	// diagnostics of nesting the lines start
+	history    *lexerHistory
+	indentFrom int
+
+	// Indentation seen so far, checked in strict mode
+	indentWith     string
+	indentWithLine int
+	indentStep     int
+	indentStepLine int
 }
 
 func newLine(line_number, column int, content string) *line {
 	return &line{
 		line:     line_number,
+		end:      line_number,
 		column:   column,
 		content:  lineContent(content),
 		children: make(lexerTree, 0),
 	}
 }
 
+// location converts an offset within the line's content to a 1-based location
+func (l *line) location(offset int) *gherkin.Location {
+	return &gherkin.Location{Line: l.line, Column: l.column + offset + 1}
+}
+
 func (l *line) branch() *line {
-	return newLine(l.line, l.content.indent(), strings.Trim(string(l.content), " \t"))
+	b := newLine(l.line, l.content.indent(), strings.Trim(string(l.content), " \t"))
+	b.end = l.end
+	b.verbatim = l.verbatim
+	return b
+}
+
+// last is the number of the last line that the subtree covers
+func (l *line) last() int {
+
+	if len(l.children) > 0 {
+		return l.children[len(l.children)-1].last()
+	}
+
+	return l.end
+}
+
+// shift moves the subtree down by a number of lines, or up if negative
+func (l *line) shift(by int) {
+
+	if by == 0 {
+		return
+	}
+
+	l.line += by
+	l.end += by
+
+	for _, child := range l.children {
+		child.shift(by)
+	}
 }
 
 func newLexer(reader io.Reader) *lexer {
 
 	lex := lexer{
-		reader: reader,
-		lines:  make(lexerTree, 0),
+		reader:      reader,
+		lines:       make(lexerTree, 0),
+		tabWidth:    defaultTabWidth,
+		diagnostics: make([]*Diagnostic, 0),
 	}
 
 	return &lex
@@ -48,6 +109,57 @@ func (s lineContent) indent() int {
 	return len(s) - len(strings.TrimLeft(string(s), " \t"))
 }
 
+// visualIndent is the width of the indentation with tabs expanded to tab stops
+func (s lineContent) visualIndent(tabWidth int) int {
+
+	width := 0
+
+	for _, c := range s[:s.indent()] {
+		if c == '\t' {
+			width += tabWidth - width%tabWidth
+		} else {
+			width++
+		}
+	}
+
+	return width
+}
+
+// docStringDelimiter returns the delimiter if the line opens a doc string
+func (s lineContent) docStringDelimiter() string {
+
+	trimmed := strings.TrimLeft(string(s), " \t")
+
+	for _, delimiter := range docStringDelimiters {
+		if strings.HasPrefix(trimmed, delimiter) {
+			return delimiter
+		}
+	}
+
+	return ""
+}
+
+// dedent removes up to width columns of leading whitespace. A tab that
+// straddles the width leaves behind the spaces that make up the difference.
+func (s lineContent) dedent(width, tabWidth int) string {
+
+	i, column := 0, 0
+
+	for ; column < width && i < len(s) && (s[i] == ' ' || s[i] == '\t'); i++ {
+		if s[i] == '\t' {
+			column += tabWidth - column%tabWidth
+		} else {
+			column++
+		}
+	}
+
+	if column > width {
+		return strings.Repeat(" ", column-width) + string(s[i:])
+	}
+
+	return string(s[i:])
+}
+
 func (l *lexer) lex() (lines lexerTree, err error) {
 
 	// Split to lines
@@ -57,53 +169,316 @@ func (l *lexer) lex() (lines lexerTree, err error) {
 	line_number := 0
 	raw_lines := make(lexerTree, 0)
 
+	var docString *line
+	delimiter := ""
+
 	for scanner.Scan() {
 		line_number++
 
+		// Doc strings are kept exactly as written until they are closed
+		if docString != nil {
+			raw := lineContent(scanner.Text())
+
+			if strings.Trim(string(raw), " \t") == delimiter {
+				docString.end = line_number
+				docString = nil
+				continue
+			}
+
+			docString.verbatim = append(docString.verbatim, raw.dedent(docString.content.visualIndent(l.tabWidth), l.tabWidth))
+			continue
+		}
+
 		text := strings.TrimRight(scanner.Text(), " \t")
 
 		if text == "" {
 			continue
 		}
 
-		raw_lines = append(raw_lines, newLine(line_number, 0, text))
+		raw_line := newLine(line_number, 0, text)
+		raw_lines = append(raw_lines, raw_line)
+
+		if err = l.checkIndentCharacters(raw_line); err != nil {
+			return nil, err
+		}
+
+		if delimiter = raw_line.content.docStringDelimiter(); delimiter != "" {
+			docString = raw_line
+			docString.verbatim = make([]string, 0)
+		}
+	}
+
+	if err = scanner.Err(); err != nil {
+		return
+	}
+
+	if docString != nil {
+		return nil, l.err(docString, "Doc string is not closed")
 	}
 
-	// Make sure the first line has no indent
-	if len(raw_lines) > 0 {
-		index := 0
-		l.indentLines(&index, raw_lines, &lines, raw_lines[0].content.indent())
+	l.indentFrom = len(l.diagnostics)
+
+	// The first line sets the outermost level, and any line outdented
+	// beyond it starts again at its own level
+	for index := 0; index < len(raw_lines); index++ {
+
+		indent := raw_lines[index].content.visualIndent(l.tabWidth)
+
+		if index > 0 {
+			if err = l.report(raw_lines[index], "Indentation of width %d is less than the first line's", indent); err != nil {
+				return nil, err
+			}
+		}
+
+		if err = l.indentLines(&index, raw_lines, &lines, indent); err != nil {
+			return nil, err
+		}
 	}
 
+	l.lines = lines
+
 	return
 }
 
-func (l *lexer) indentLines(index *int, input lexerTree, output *lexerTree, indent int) {
+func (l *lexer) err(raw_line *line, e string, args ...interface{}) error {
+
+	message := fmt.Sprintf(e, args...)
+	column := raw_line.content.indent() + 1
+
+	l.diagnostics = append(l.diagnostics, newDiagnostic(SeverityError, raw_line.line, column, message))
+
+	return fmt.Errorf("[Line %04d:%02d] %s", raw_line.line, column, message)
+}
+
+// report is an error in strict mode, and otherwise only a warning
+func (l *lexer) report(raw_line *line, e string, args ...interface{}) error {
+
+	if l.strict {
+		return l.err(raw_line, e, args...)
+	}
+
+	l.diagnostics = append(l.diagnostics, newDiagnostic(SeverityWarning, raw_line.line, raw_line.content.indent()+1, fmt.Sprintf(e, args...)))
+
+	return nil
+}
+
+func (l *lexer) checkIndentCharacters(raw_line *line) error {
+
+	whitespace := string(raw_line.content[:raw_line.content.indent()])
+	tabs, spaces := strings.Contains(whitespace, "\t"), strings.Contains(whitespace, " ")
+
+	if tabs && spaces {
+		return l.report(raw_line, "Indentation mixes tabs and spaces")
+	}
+
+	with := "spaces"
+
+	if tabs {
+		with = "tabs"
+	} else if !spaces {
+		return nil
+	}
+
+	if l.indentWith == "" {
+		l.indentWith, l.indentWithLine = with, raw_line.line
+
+	} else if l.indentWith != with {
+		return l.report(raw_line, "Indentation uses %s, but line %d uses %s", with, l.indentWithLine, l.indentWith)
+	}
+
+	return nil
+}
+
+func (l *lexer) checkIndentStep(raw_line *line, step int) error {
+
+	if l.indentStep == 0 {
+		l.indentStep, l.indentStepLine = step, raw_line.line
+
+	} else if l.indentStep != step {
+		return l.err(raw_line, "Inconsistent indentation: indented by %d, but line %d is indented by %d", step, l.indentStepLine, l.indentStep)
+	}
+
+	return nil
+}
+
+func (l *lexer) indentLines(index *int, input lexerTree, output *lexerTree, indent int) error {
 
 	// Ends when there are no more lines
 	if *index >= len(input) {
-		return
+		return nil
 	}
 
 	var line_to_add *line
+	dedented := false
 
 	for ; *index < len(input); *index++ {
 
-		line_indent := input[*index].content.indent()
+		line_indent := input[*index].content.visualIndent(l.tabWidth)
+
+		snapped := false
+
+		// Coming back out of the children, this line should have matched a
+		// level that already exists
+		if dedented && line_indent > indent {
+
+			if err := l.report(input[*index], "Indentation of width %d doesn't match any outer level, so is treated as width %d", line_indent, indent); err != nil {
+				return err
+			}
+
+			line_indent, snapped = indent, true
+		}
+
+		dedented = false
 
 		if line_indent == indent {
-			line_to_add = input[*index].branch()
+
+			// A subtree is only the same if it's nested at its own indentation
+			if line_to_add = nil; !snapped {
+				line_to_add = l.reuse(index, input)
+			}
+
+			if line_to_add == nil {
+				line_to_add = input[*index].branch()
+			}
+
 			*output = append(*output, line_to_add)
 
 		} else if line_indent > indent {
-			l.indentLines(index, input, &line_to_add.children, line_indent)
+
+			if l.strict {
+				if err := l.checkIndentStep(input[*index], line_indent-indent); err != nil {
+					return err
+				}
+			}
+
+			if err := l.indentLines(index, input, &line_to_add.children, line_indent); err != nil {
+				return err
+			}
+
+			dedented = true
 
 		} else if line_indent < indent {
 			*index--
-			return
+			return nil
 		}
 
 	}
 
-	return
+	return nil
+}
+
+// reuse returns the subtree of an earlier lex in place of the line at index,
+// and moves the index to the subtree's last line. The subtree is only reused
+// when none of its lines have changed and no following line would now join it.
+func (l *lexer) reuse(index *int, input lexerTree) *line {
+
+	// Strict mode checks every step of indentation as it goes
+	if l.history == nil || l.strict {
+		return nil
+	}
+
+	subtree, shift := l.history.subtree(input[*index].line)
+
+	if subtree == nil {
+		return nil
+	}
+
+	first, last := subtree.line, subtree.last()
+
+	// Nor is it if its first line was reported, as when it was snapped to
+	// an outer level
+	for _, d := range l.history.diagnostics {
+		if d.Location.Line == first {
+			return nil
+		}
+	}
+
+	next := *index + 1
+
+	for next < len(input) && input[next].line <= last+shift {
+		next++
+	}
+
+	if next < len(input) && input[next].content.visualIndent(l.tabWidth) > input[*index].content.visualIndent(l.tabWidth) {
+		return nil
+	}
+
+	// Warnings about the nesting within the subtree still apply
+	for _, d := range l.history.diagnostics {
+		if d.Location.Line > first && d.Location.Line <= last {
+			l.diagnostics = append(l.diagnostics, newDiagnostic(d.Severity, d.Location.Line+shift, d.Location.Column, d.Message))
+		}
+	}
+
+	subtree.shift(shift)
+	*index = next - 1
+
+	return subtree
+}
+
+// indentDiagnostics are the diagnostics found while nesting the lines
+func (l *lexer) indentDiagnostics() []*Diagnostic {
+	return l.diagnostics[l.indentFrom:]
+}
+
+// lexerHistory is the tree of an earlier lex, and which of the lines now
+// being lexed are unchanged since
+type lexerHistory struct {
+	nodes       []*line       // The earlier tree's lines, by line number
+	origin      []int         // The earlier number of each line, or 0 if it has changed
+	unchanged   []int         // How many lines from each line on are unchanged and in order
+	diagnostics []*Diagnostic // The earlier indentDiagnostics
+}
+
+func newLexerHistory(tree lexerTree, origin []int, diagnostics []*Diagnostic) *lexerHistory {
+
+	h := &lexerHistory{origin: origin, unchanged: make([]int, len(origin)+1), diagnostics: diagnostics}
+
+	var add func(tree lexerTree)
+
+	add = func(tree lexerTree) {
+		for _, l := range tree {
+			for len(h.nodes) <= l.line {
+				h.nodes = append(h.nodes, nil)
+			}
+
+			h.nodes[l.line] = l
+			add(l.children)
+		}
+	}
+
+	add(tree)
+
+	for i := len(origin) - 1; i >= 0; i-- {
+		if origin[i] == 0 {
+			continue
+		}
+
+		h.unchanged[i] = 1
+
+		if i+1 < len(origin) && origin[i+1] == origin[i]+1 {
+			h.unchanged[i] += h.unchanged[i+1]
+		}
+	}
+
+	return h
+}
+
+// subtree returns the earlier subtree at a line, and how far it has moved,
+// if none of the lines it covers have changed
+func (h *lexerHistory) subtree(line_number int) (*line, int) {
+
+	i := line_number - 1
+
+	if i < 0 || i >= len(h.origin) || h.origin[i] == 0 || h.origin[i] >= len(h.nodes) {
+		return nil, 0
+	}
+
+	subtree := h.nodes[h.origin[i]]
+
+	if subtree == nil || h.unchanged[i] < subtree.last()-subtree.line+1 {
+		return nil, 0
+	}
+
+	return subtree, line_number - subtree.line
 }
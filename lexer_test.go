@@ -97,6 +97,167 @@ Another other line of blurb
 	}
 }
 
+func Test_LexerKeepsDocStringsVerbatim(t *testing.T) {
+
+	file := `Actor: Valid actor
+    """markdown
+    # Heading
+
+      * indented bullet
+    Actor: not a keyword
+  less indented
+    """
+    Goal: Goal number 1`
+
+	lines, err := newLexer(bytes.NewBufferString(file)).lex()
+	assert.Nil(t, err)
+
+	compareLexerTrees(t, lines, lexerTree{
+		&line{line: 1, column: 0, content: "Actor: Valid actor", children: []*line{
+			&line{line: 2, column: 4, content: `"""markdown`},
+			&line{line: 9, column: 4, content: "Goal: Goal number 1"},
+		}},
+	}, 0)
+
+	assert.Equal(t, []string{
+		"# Heading",
+		"",
+		"  * indented bullet",
+		"Actor: not a keyword",
+		"less indented",
+	}, lines[0].children[0].verbatim)
+}
+
+func Test_LexerRejectsUnclosedDocStrings(t *testing.T) {
+
+	lines, err := newLexer(bytes.NewBufferString("Actor: Valid actor\n    ```\n    text")).lex()
+
+	assert.Nil(t, lines)
+	assert.Equal(t, fmt.Errorf("[Line 0002:05] Doc string is not closed"), err)
+}
+
+func Test_IndentationIsMeasuredWithTabStops(t *testing.T) {
+
+	assert.Equal(t, 4, lineContent("\tx").visualIndent(4))
+	assert.Equal(t, 8, lineContent("\tx").visualIndent(8))
+	assert.Equal(t, 4, lineContent("  \tx").visualIndent(4))
+	assert.Equal(t, 8, lineContent("    \t  x").visualIndent(2))
+
+	assert.Equal(t, "x", lineContent("\tx").dedent(4, 4))
+	assert.Equal(t, "  x", lineContent("\tx").dedent(2, 4))
+	assert.Equal(t, "\tx", lineContent("  \tx").dedent(2, 4))
+}
+
+func Test_LexerNestsLinesByVisualIndentation(t *testing.T) {
+
+	file := "Actor: Valid actor\n\tTab indented\n        Eight spaces"
+
+	var inputs = []struct {
+		tabWidth      int
+		expected_tree lexerTree
+	}{
+		{
+			tabWidth: 4,
+			expected_tree: lexerTree{
+				&line{line: 1, column: 0, content: "Actor: Valid actor", children: []*line{
+					&line{line: 2, column: 1, content: "Tab indented", children: []*line{
+						&line{line: 3, column: 8, content: "Eight spaces"},
+					}},
+				}},
+			},
+		},
+		{
+			tabWidth: 8,
+			expected_tree: lexerTree{
+				&line{line: 1, column: 0, content: "Actor: Valid actor", children: []*line{
+					&line{line: 2, column: 1, content: "Tab indented"},
+					&line{line: 3, column: 8, content: "Eight spaces"},
+				}},
+			},
+		},
+	}
+
+	for _, input := range inputs {
+		lex := newLexer(bytes.NewBufferString(file))
+		lex.tabWidth = input.tabWidth
+
+		lines, err := lex.lex()
+		assert.Nil(t, err)
+		compareLexerTrees(t, lines, input.expected_tree, 0)
+
+		assert.Equal(t, []*Diagnostic{
+			newDiagnostic(SeverityWarning, 3, 9, "Indentation uses spaces, but line 2 uses tabs"),
+		}, lex.diagnostics)
+	}
+}
+
+func Test_LexerReportsInconsistentIndentation(t *testing.T) {
+
+	var inputs = []struct {
+		file          string
+		expected_tree lexerTree
+		diagnostics   []*Diagnostic
+	}{
+		{
+			// Dedenting to a level that never existed
+			file: "Actor: Valid actor\n    Goals:\n        Goal 1\n      Blurb",
+			expected_tree: lexerTree{
+				&line{line: 1, column: 0, content: "Actor: Valid actor", children: []*line{
+					&line{line: 2, column: 4, content: "Goals:", children: []*line{
+						&line{line: 3, column: 8, content: "Goal 1"},
+					}},
+					&line{line: 4, column: 6, content: "Blurb"},
+				}},
+			},
+			diagnostics: []*Diagnostic{
+				newDiagnostic(SeverityWarning, 4, 7, "Indentation of width 6 doesn't match any outer level, so is treated as width 4"),
+			},
+		},
+		{
+			// A single line mixing tabs and spaces
+			file: "Actor: Valid actor\n \tBlurb",
+			expected_tree: lexerTree{
+				&line{line: 1, column: 0, content: "Actor: Valid actor", children: []*line{
+					&line{line: 2, column: 2, content: "Blurb"},
+				}},
+			},
+			diagnostics: []*Diagnostic{
+				newDiagnostic(SeverityWarning, 2, 3, "Indentation mixes tabs and spaces"),
+			},
+		},
+		{
+			// Outdented beyond the first line
+			file: "  Actor: Valid actor\n      Blurb\nGoal: Goal 1",
+			expected_tree: lexerTree{
+				&line{line: 1, column: 2, content: "Actor: Valid actor", children: []*line{
+					&line{line: 2, column: 6, content: "Blurb"},
+				}},
+				&line{line: 3, column: 0, content: "Goal: Goal 1"},
+			},
+			diagnostics: []*Diagnostic{
+				newDiagnostic(SeverityWarning, 3, 1, "Indentation of width 0 is less than the first line's"),
+			},
+		},
+	}
+
+	for _, input := range inputs {
+		lex := newLexer(bytes.NewBufferString(input.file))
+		lines, err := lex.lex()
+
+		assert.Nil(t, err)
+		compareLexerTrees(t, lines, input.expected_tree, 0)
+		assert.Equal(t, input.diagnostics, lex.diagnostics)
+
+		// Strict mode refuses to guess
+		lex = newLexer(bytes.NewBufferString(input.file))
+		lex.strict = true
+		lines, err = lex.lex()
+
+		assert.Nil(t, lines)
+		assert.NotNil(t, err)
+	}
+}
+
 func Test_CanLoadValidActorDefinitionFromFile(t *testing.T) {
 
 	var inputs = []struct {
@@ -108,7 +269,7 @@ func Test_CanLoadValidActorDefinitionFromFile(t *testing.T) {
 			expected_tree: lexerTree{
 				&line{line: 1, column: 0, content: "@tag1 @tag2"},
 				&line{line: 2, column: 0, content: "Actor: Valid actor", children: []*line{
-					&line{line: 3, column: 4, content: "Description and blurb... multi line 1 tab indent"},
+					&line{line: 3, column: 4, content: "Description and blurb... indented one level"},
 					&line{line: 4, column: 4, content: "Some other line of blurb"},
 					&line{line: 6, column: 4, content: "@tag3 @tag4"},
 					&line{line: 7, column: 4, content: "Goals:", children: []*line{
@@ -136,7 +297,7 @@ func Test_CanLoadValidActorDefinitionFromFile(t *testing.T) {
 }
 
 func compareLexerTrees(t *testing.T, a, b lexerTree, indent int) {
-	assert.Equal(t, len(a), len(b), fmt.Sprintf("Index %d: Trees should be of equal length"))
+	assert.Equal(t, len(a), len(b), fmt.Sprintf("Indent %d: Trees should be of equal length", indent))
 
 	for i := 0; i < len(a); i++ {
 		assert.Equal(t, a[i].line, b[i].line)
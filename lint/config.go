@@ -0,0 +1,97 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/dryvercorp/actor"
+)
+
+// DefaultConfigFile is where the actor command looks for a configuration
+const DefaultConfigFile = ".actor-lint.json"
+
+// Config sets up the rules by name, as in:
+//
+//	{
+//	    "rules": {
+//	        "goal-length": {"severity": "error", "options": {"max": 40}},
+//	        "empty-blurb": {"disabled": true},
+//	        "known-tags": {"options": {"tags": ["admin", "wip"]}}
+//	    }
+//	}
+type Config struct {
+	Rules map[string]*RuleConfig `json:"rules"`
+}
+
+type RuleConfig struct {
+	Disabled bool `json:"disabled,omitempty"`
+
+	// Severity defaults to a warning
+	Severity *actor.Severity `json:"severity,omitempty"`
+
+	// Options are specific to each rule
+	Options json.RawMessage `json:"options,omitempty"`
+}
+
+func ReadConfig(r io.Reader) (*Config, error) {
+
+	config := &Config{}
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(config); err != nil {
+		return nil, fmt.Errorf("Invalid lint configuration: %s", err)
+	}
+
+	return config, nil
+}
+
+func ReadConfigFile(path string) (*Config, error) {
+
+	file, err := os.Open(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	return ReadConfig(file)
+}
+
+func (c *Config) rule(name string) *RuleConfig {
+
+	if config, ok := c.Rules[name]; ok && config != nil {
+		return config
+	}
+
+	return &RuleConfig{}
+}
+
+func (r *RuleConfig) severity() actor.Severity {
+
+	if r.Severity == nil {
+		return actor.SeverityWarning
+	}
+
+	return *r.Severity
+}
+
+func (r *RuleConfig) options(v interface{}) error {
+
+	if len(r.Options) == 0 {
+		return nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(r.Options))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("Invalid options: %s", err)
+	}
+
+	return nil
+}
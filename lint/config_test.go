@@ -0,0 +1,49 @@
+package lint
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dryvercorp/actor"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ItReadsConfiguration(t *testing.T) {
+
+	config, err := ReadConfig(bytes.NewBufferString(`{
+		"rules": {
+			"goal-length": {"severity": "error", "options": {"max": 10}},
+			"empty-blurb": {"disabled": true}
+		}
+	}`))
+
+	assert.Nil(t, err)
+	assert.Equal(t, actor.SeverityError, config.rule("goal-length").severity())
+	assert.True(t, config.rule("empty-blurb").Disabled)
+	assert.Equal(t, actor.SeverityWarning, config.rule("goal-verb").severity())
+
+	assert.Equal(t, []string{
+		"[Line 0003:11] error: Goal name is 17 characters long, more than 10 (goal-length)",
+	}, lintText(t, config, "\nActor: Admin\n    Goal: Moderate comments\n"))
+}
+
+func Test_ItRejectsBadConfiguration(t *testing.T) {
+
+	for _, test := range []struct {
+		config   string
+		expected string
+	}{
+		{`{"rules": {"goal-length": {"severity": "fatal"}}}`, "Invalid lint configuration: Unknown severity 'fatal'"},
+		{`{"rulez": {}}`, `Invalid lint configuration: json: unknown field "rulez"`},
+	} {
+		_, err := ReadConfig(bytes.NewBufferString(test.config))
+		assert.Equal(t, test.expected, err.Error())
+	}
+
+	config := &Config{Rules: map[string]*RuleConfig{"goal-length": {Options: []byte(`{"maximum": 10}`)}}}
+	linter, err := New(config)
+	assert.Nil(t, err)
+
+	_, err = linter.Lint(parse(t, "Actor: Admin\n"))
+	assert.Equal(t, `Rule 'goal-length': Invalid options: json: unknown field "maximum"`, err.Error())
+}
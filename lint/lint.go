@@ -0,0 +1,271 @@
+// Package lint checks the style and quality of actors, beyond what the parser
+// requires. Each check is a Rule, configured by a Config, and a line can opt
+// out of rules with a comment:
+//
+//	Goal: do things # actor-lint:disable goal-verb
+//
+//	# actor-lint:disable-next-line goal-length, goal-verb
+//	Goal: ...
+//
+// Without a list of rules, every rule is disabled for the line.
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	gherkin "github.com/cucumber/gherkin-go"
+	"github.com/dryvercorp/actor"
+)
+
+// Rule is a single check, run against each actor in turn
+type Rule interface {
+	// Name identifies the rule in configuration and in disable comments
+	Name() string
+	Description() string
+
+	// Check reports each problem it finds through the context
+	Check(c *Context, a *actor.Actor) error
+}
+
+// Problem is something a rule found wrong with an actor
+type Problem struct {
+	Rule     string            `json:"rule"`
+	Location *gherkin.Location `json:"location"`
+	Severity actor.Severity    `json:"severity"`
+	Message  string            `json:"message"`
+}
+
+// String writes the problem with its location, which is nil for an actor
+// that wasn't parsed, such as one made by actor.NewBuilder
+func (p *Problem) String() string {
+
+	if p.Location == nil {
+		return fmt.Sprintf("%s: %s (%s)", p.Severity, p.Message, p.Rule)
+	}
+
+	return fmt.Sprintf("[Line %04d:%02d] %s: %s (%s)", p.Location.Line, p.Location.Column, p.Severity, p.Message, p.Rule)
+}
+
+// Result is the problems found in one file of a project
+type Result struct {
+	Path     string
+	Problems []*Problem
+}
+
+// Context is what a rule sees besides the actor it is checking
+type Context struct {
+	// Actors is every actor being linted together, for rules that look
+	// across files
+	Actors []*actor.Actor
+
+	actor    *actor.Actor
+	tags     map[string]int
+	rule     Rule
+	config   *RuleConfig
+	problems []*Problem
+}
+
+// Options unmarshals the rule's options from the configuration into v, and
+// leaves v alone if there are none
+func (c *Context) Options(v interface{}) error {
+	return c.config.options(v)
+}
+
+// TagCount is how many times a tag is written on the actors and their goals
+func (c *Context) TagCount(name string) int {
+
+	if c.tags == nil {
+		c.tags = make(map[string]int)
+
+		for _, a := range c.Actors {
+			for _, tag := range allTags(a) {
+				c.tags[tag.Name]++
+			}
+		}
+	}
+
+	return c.tags[name]
+}
+
+// Report reports a problem at a location, or at the actor's if the location
+// is nil
+func (c *Context) Report(location *gherkin.Location, format string, args ...interface{}) {
+
+	if location == nil && c.actor != nil {
+		location = c.actor.Location
+	}
+
+	c.problems = append(c.problems, &Problem{
+		Rule:     c.rule.Name(),
+		Location: location,
+		Severity: c.config.severity(),
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+type Linter struct {
+	rules  []Rule
+	config *Config
+}
+
+// New returns a linter with the given rules, or with DefaultRules if there
+// are none. Every rule named by the configuration must be one of them.
+func New(config *Config, rules ...Rule) (*Linter, error) {
+
+	if len(rules) == 0 {
+		rules = DefaultRules()
+	}
+
+	if config == nil {
+		config = &Config{}
+	}
+
+	known := make(map[string]bool)
+
+	for _, rule := range rules {
+		known[rule.Name()] = true
+	}
+
+	for name := range config.Rules {
+		if !known[name] {
+			return nil, fmt.Errorf("Unknown rule '%s'", name)
+		}
+	}
+
+	return &Linter{rules: rules, config: config}, nil
+}
+
+func (l *Linter) Rules() []Rule {
+	return l.rules
+}
+
+// Lint checks a single actor on its own
+func (l *Linter) Lint(a *actor.Actor) ([]*Problem, error) {
+	return l.lint(&Context{Actors: []*actor.Actor{a}}, a)
+}
+
+// LintProject checks every actor in the project that parsed
+func (l *Linter) LintProject(p *actor.Project) ([]*Result, error) {
+
+	c := &Context{Actors: p.Actors()}
+	results := make([]*Result, 0, len(c.Actors))
+
+	for _, file := range p.Files {
+
+		if file.Actor == nil {
+			continue
+		}
+
+		problems, err := l.lint(c, file.Actor)
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", file.Path, err)
+		}
+
+		results = append(results, &Result{Path: file.Path, Problems: problems})
+	}
+
+	return results, nil
+}
+
+func (l *Linter) lint(c *Context, a *actor.Actor) ([]*Problem, error) {
+
+	c.actor, c.problems = a, make([]*Problem, 0)
+
+	for _, rule := range l.rules {
+
+		config := l.config.rule(rule.Name())
+
+		if config.Disabled {
+			continue
+		}
+
+		c.rule, c.config = rule, config
+
+		if err := rule.Check(c, a); err != nil {
+			return nil, fmt.Errorf("Rule '%s': %s", rule.Name(), err)
+		}
+	}
+
+	disabled := disabledRules(a)
+	problems := make([]*Problem, 0, len(c.problems))
+
+	for _, problem := range c.problems {
+		if !disabled.covers(problem) {
+			problems = append(problems, problem)
+		}
+	}
+
+	// Problems without a location come last
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].Location == nil || problems[j].Location == nil {
+			return problems[j].Location == nil && problems[i].Location != nil
+		}
+
+		if problems[i].Location.Line != problems[j].Location.Line {
+			return problems[i].Location.Line < problems[j].Location.Line
+		}
+
+		return problems[i].Location.Column < problems[j].Location.Column
+	})
+
+	return problems, nil
+}
+
+var disableMatcher = regexp.MustCompile(`^#\s*actor-lint:(disable-next-line|disable)\b(.*)$`)
+
+// disabled maps line numbers to the rules disabled on them, where an empty
+// list disables every rule
+type disabled map[int][]string
+
+func disabledRules(a *actor.Actor) disabled {
+
+	d := make(disabled)
+
+	for _, comment := range a.Comments {
+
+		matches := disableMatcher.FindStringSubmatch(strings.TrimSpace(comment.Text))
+
+		if matches == nil || comment.Location == nil {
+			continue
+		}
+
+		line := comment.Location.Line
+
+		if matches[1] == "disable-next-line" {
+			line++
+		}
+
+		d[line] = strings.FieldsFunc(matches[2], func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+	}
+
+	return d
+}
+
+func (d disabled) covers(problem *Problem) bool {
+
+	if problem.Location == nil {
+		return false
+	}
+
+	rules, ok := d[problem.Location.Line]
+
+	if !ok {
+		return false
+	}
+
+	if len(rules) == 0 {
+		return true
+	}
+
+	for _, rule := range rules {
+		if rule == problem.Rule {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,168 @@
+package lint
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/dryvercorp/actor"
+	"github.com/stretchr/testify/assert"
+)
+
+func parse(t *testing.T, text string) *actor.Actor {
+
+	a, err := actor.NewParser(bytes.NewBufferString(text)).Parse()
+	assert.Nil(t, err)
+
+	return a
+}
+
+func lintText(t *testing.T, config *Config, text string) []string {
+
+	linter, err := New(config)
+	assert.Nil(t, err)
+
+	problems, err := linter.Lint(parse(t, text))
+	assert.Nil(t, err)
+
+	found := make([]string, 0, len(problems))
+
+	for _, p := range problems {
+		found = append(found, p.String())
+	}
+
+	return found
+}
+
+func Test_ItLintsAnActor(t *testing.T) {
+
+	problems := lintText(t, nil, `
+Actor: administrator
+    Goals:
+        The moderation of comments
+        Publish articles
+        publish  articles
+`)
+
+	assert.Equal(t, []string{
+		"[Line 0002:01] warning: Actor 'administrator' has no description (empty-blurb)",
+		"[Line 0002:08] warning: Actor name 'administrator' should start with a capital letter (actor-name-case)",
+		"[Line 0004:09] warning: Goal 'The moderation of comments' should start with a verb (goal-verb)",
+		"[Line 0006:09] warning: Goal 'publish  articles' duplicates the goal on line 5 (duplicate-goal)",
+	}, problems)
+}
+
+func Test_ItLintsABuiltActor(t *testing.T) {
+
+	a, err := actor.NewBuilder("administrator").Goal("The moderation of comments").Goal("Publish articles").Build()
+	assert.Nil(t, err)
+
+	a.Goals = append(a.Goals, &actor.Goal{Name: "publish  articles"})
+
+	linter, err := New(nil)
+	assert.Nil(t, err)
+
+	problems, err := linter.Lint(a)
+	assert.Nil(t, err)
+
+	found := make([]string, 0, len(problems))
+
+	for _, p := range problems {
+		assert.Nil(t, p.Location)
+		found = append(found, p.String())
+	}
+
+	assert.Equal(t, []string{
+		"warning: Actor name 'administrator' should start with a capital letter (actor-name-case)",
+		"warning: Goal 'The moderation of comments' should start with a verb (goal-verb)",
+		"warning: Goal 'publish  articles' duplicates the goal 'Publish articles' (duplicate-goal)",
+		"warning: Actor 'administrator' has no description (empty-blurb)",
+	}, found)
+
+	// A goal added to a parsed actor is reported at the actor
+	a = parse(t, "Actor: Administrator\n    Publishes articles\n")
+	_, err = a.AddGoal("The moderation of comments")
+	assert.Nil(t, err)
+
+	problems, err = linter.Lint(a)
+	assert.Nil(t, err)
+
+	if assert.Equal(t, 1, len(problems)) {
+		assert.Equal(t, "[Line 0001:01] warning: Goal 'The moderation of comments' should start with a verb (goal-verb)", problems[0].String())
+	}
+}
+
+func Test_ItHonoursDisableComments(t *testing.T) {
+
+	problems := lintText(t, nil, `
+Actor: administrator # actor-lint:disable
+    Manages the site
+
+    # actor-lint:disable-next-line goal-verb, duplicate-goal
+    Goal: The moderation of comments
+    Goal: The moderation of comments # actor-lint:disable goal-verb
+    Goal: The moderation of comments # actor-lint:disable duplicate-goal
+`)
+
+	assert.Equal(t, []string{
+		"[Line 0007:11] warning: Goal 'The moderation of comments' duplicates the goal on line 6 (duplicate-goal)",
+		"[Line 0008:11] warning: Goal 'The moderation of comments' should start with a verb (goal-verb)",
+	}, problems)
+}
+
+type noAdmins struct{}
+
+func (r *noAdmins) Name() string        { return "no-admins" }
+func (r *noAdmins) Description() string { return "Nobody is an admin" }
+
+func (r *noAdmins) Check(c *Context, a *actor.Actor) error {
+
+	if a.Name == "Admin" {
+		c.Report(a.NameLocation, "No admins")
+	}
+
+	return nil
+}
+
+func Test_ItRunsTheGivenRules(t *testing.T) {
+
+	linter, err := New(&Config{Rules: map[string]*RuleConfig{"no-admins": {}}}, &noAdmins{})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(linter.Rules()))
+
+	problems, err := linter.Lint(parse(t, "Actor: Admin\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, []*Problem{{Rule: "no-admins", Location: problems[0].Location, Severity: actor.SeverityWarning, Message: "No admins"}}, problems)
+
+	_, err = New(&Config{Rules: map[string]*RuleConfig{"no-admins": {}}})
+	assert.Equal(t, "Unknown rule 'no-admins'", err.Error())
+}
+
+func Test_ItLintsAProject(t *testing.T) {
+
+	dir := t.TempDir()
+
+	for name, content := range map[string]string{
+		"admin.actor":  "@staff @admin\nActor: Admin\n    Manages the site\n",
+		"editor.actor": "@staff\nActor: Editor\n    Edits the site\n",
+		"broken.actor": "Actor:\n",
+	} {
+		assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+
+	project, err := actor.LoadProject(dir, actor.ParserOptions{})
+	assert.Nil(t, err)
+
+	linter, err := New(nil)
+	assert.Nil(t, err)
+
+	results, err := linter.LintProject(project)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, len(results))
+	assert.Equal(t, filepath.Join(dir, "admin.actor"), results[0].Path)
+	assert.Equal(t, 1, len(results[0].Problems))
+	assert.Equal(t, "[Line 0001:08] warning: Tag '@admin' is only used once (single-use-tag)", results[0].Problems[0].String())
+	assert.Equal(t, 0, len(results[1].Problems))
+}
@@ -0,0 +1,303 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	gherkin "github.com/cucumber/gherkin-go"
+	"github.com/dryvercorp/actor"
+)
+
+// DefaultRules returns a new instance of every rule in the package
+func DefaultRules() []Rule {
+	return []Rule{
+		&actorNameCase{},
+		&goalVerb{},
+		&duplicateGoal{},
+		&emptyBlurb{},
+		&goalLength{},
+		&knownTags{},
+		&singleUseTag{},
+	}
+}
+
+func startsLower(word string) bool {
+	r, _ := utf8.DecodeRuneInString(word)
+	return unicode.IsLower(r)
+}
+
+// allTags returns the tags of the actor and of its goals, where the goals of
+// a Goals: list share the tags written above it
+func allTags(a *actor.Actor) []*gherkin.Tag {
+
+	tags := make([]*gherkin.Tag, 0)
+	seen := make(map[*gherkin.Tag]bool)
+
+	actor.Inspect(a, func(node actor.Node) bool {
+
+		if tag, ok := node.(*gherkin.Tag); ok && !seen[tag] {
+			tags = append(tags, tag)
+			seen[tag] = true
+		}
+
+		return true
+	})
+
+	return tags
+}
+
+func errUnknownOption(option, value string) error {
+	return fmt.Errorf("Unknown %s '%s'", option, value)
+}
+
+type actorNameCase struct{}
+
+// Words that may stay lower case in a title
+var minorWords = map[string]bool{
+	"a": true, "an": true, "and": true, "at": true, "by": true, "for": true, "in": true,
+	"of": true, "on": true, "or": true, "the": true, "to": true, "with": true,
+}
+
+func (r *actorNameCase) Name() string {
+	return "actor-name-case"
+}
+
+func (r *actorNameCase) Description() string {
+	return "Actor names start with a capital letter, or with the \"title\" style, every word but minor ones does"
+}
+
+func (r *actorNameCase) Check(c *Context, a *actor.Actor) error {
+
+	options := struct {
+		Style string `json:"style"`
+	}{Style: "sentence"}
+
+	if err := c.Options(&options); err != nil {
+		return err
+	}
+
+	words := strings.Fields(a.Name)
+
+	switch options.Style {
+	case "sentence":
+		if len(words) > 0 && startsLower(words[0]) {
+			c.Report(a.NameLocation, "Actor name '%s' should start with a capital letter", a.Name)
+		}
+
+	case "title":
+		for i, word := range words {
+			if startsLower(word) && (i == 0 || !minorWords[word]) {
+				c.Report(a.NameLocation, "Actor name '%s' should be in title case", a.Name)
+				break
+			}
+		}
+
+	default:
+		return errUnknownOption("style", options.Style)
+	}
+
+	return nil
+}
+
+type goalVerb struct{}
+
+// Words that start a goal but aren't verbs
+var notVerbs = map[string]bool{
+	"a": true, "an": true, "the": true, "to": true, "i": true, "we": true, "my": true,
+	"our": true, "their": true, "his": true, "her": true, "its": true, "this": true,
+	"that": true, "some": true, "all": true, "every": true, "each": true, "more": true,
+	"new": true, "when": true, "if": true, "being": true,
+}
+
+func (r *goalVerb) Name() string {
+	return "goal-verb"
+}
+
+func (r *goalVerb) Description() string {
+	return "Goals start with a verb, as in \"Publish articles\", or with one of the configured verbs"
+}
+
+func (r *goalVerb) Check(c *Context, a *actor.Actor) error {
+
+	options := struct {
+		Verbs []string `json:"verbs"`
+	}{}
+
+	if err := c.Options(&options); err != nil {
+		return err
+	}
+
+	verbs := make(map[string]bool)
+
+	for _, verb := range options.Verbs {
+		verbs[strings.ToLower(verb)] = true
+	}
+
+	for _, goal := range a.Goals {
+
+		words := strings.Fields(goal.Name)
+
+		if len(words) == 0 {
+			continue
+		}
+
+		first := strings.ToLower(words[0])
+
+		if (len(verbs) > 0 && !verbs[first]) || (len(verbs) == 0 && notVerbs[first]) {
+			c.Report(goal.NameLocation, "Goal '%s' should start with a verb", goal.Name)
+		}
+	}
+
+	return nil
+}
+
+type duplicateGoal struct{}
+
+func (r *duplicateGoal) Name() string {
+	return "duplicate-goal"
+}
+
+func (r *duplicateGoal) Description() string {
+	return "An actor's goals are all different, ignoring case and spacing"
+}
+
+func (r *duplicateGoal) Check(c *Context, a *actor.Actor) error {
+
+	// Goals are matched as FindGoal matches them, which finds the first
+	for _, goal := range a.Goals {
+
+		first := a.FindGoal(goal.Name)
+
+		if first == goal {
+			continue
+		}
+
+		if first.Location == nil {
+			c.Report(goal.NameLocation, "Goal '%s' duplicates the goal '%s'", goal.Name, first.Name)
+		} else {
+			c.Report(goal.NameLocation, "Goal '%s' duplicates the goal on line %d", goal.Name, first.Location.Line)
+		}
+	}
+
+	return nil
+}
+
+type emptyBlurb struct{}
+
+func (r *emptyBlurb) Name() string {
+	return "empty-blurb"
+}
+
+func (r *emptyBlurb) Description() string {
+	return "Actors have a description, in blurb or a doc string"
+}
+
+func (r *emptyBlurb) Check(c *Context, a *actor.Actor) error {
+
+	for _, line := range a.Blurb {
+		if strings.TrimSpace(line) != "" {
+			return nil
+		}
+	}
+
+	if a.DocString == nil || strings.TrimSpace(a.DocString.Content) == "" {
+		c.Report(a.Location, "Actor '%s' has no description", a.Name)
+	}
+
+	return nil
+}
+
+type goalLength struct{}
+
+func (r *goalLength) Name() string {
+	return "goal-length"
+}
+
+func (r *goalLength) Description() string {
+	return "Goal names are no longer than a maximum number of characters, 60 by default"
+}
+
+func (r *goalLength) Check(c *Context, a *actor.Actor) error {
+
+	options := struct {
+		Max int `json:"max"`
+	}{Max: 60}
+
+	if err := c.Options(&options); err != nil {
+		return err
+	}
+
+	for _, goal := range a.Goals {
+		if length := utf8.RuneCountInString(goal.Name); length > options.Max {
+			c.Report(goal.NameLocation, "Goal name is %d characters long, more than %d", length, options.Max)
+		}
+	}
+
+	return nil
+}
+
+type knownTags struct{}
+
+func (r *knownTags) Name() string {
+	return "known-tags"
+}
+
+func (r *knownTags) Description() string {
+	return "Every tag is in the configured list of tags, if there is one"
+}
+
+func (r *knownTags) Check(c *Context, a *actor.Actor) error {
+
+	options := struct {
+		Tags []string `json:"tags"`
+	}{}
+
+	if err := c.Options(&options); err != nil {
+		return err
+	}
+
+	if len(options.Tags) == 0 {
+		return nil
+	}
+
+	known := make(map[string]bool)
+
+	for _, tag := range options.Tags {
+		known[strings.TrimPrefix(tag, "@")] = true
+	}
+
+	for _, tag := range allTags(a) {
+		if !known[tag.Name] {
+			c.Report(tag.Location, "Tag '@%s' is not a known tag", tag.Name)
+		}
+	}
+
+	return nil
+}
+
+type singleUseTag struct{}
+
+func (r *singleUseTag) Name() string {
+	return "single-use-tag"
+}
+
+func (r *singleUseTag) Description() string {
+	return "Every tag is used more than once across the actors linted together, when there are several"
+}
+
+func (r *singleUseTag) Check(c *Context, a *actor.Actor) error {
+
+	if len(c.Actors) < 2 {
+		return nil
+	}
+
+	for _, tag := range allTags(a) {
+		if c.TagCount(tag.Name) == 1 {
+			c.Report(tag.Location, "Tag '@%s' is only used once", tag.Name)
+		}
+	}
+
+	return nil
+}
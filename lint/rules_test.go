@@ -0,0 +1,64 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Rules(t *testing.T) {
+
+	for _, test := range []struct {
+		rule     string
+		options  string
+		actor    string
+		expected []string
+	}{
+		{"actor-name-case", ``, "Actor: site admin", []string{"Actor name 'site admin' should start with a capital letter"}},
+		{"actor-name-case", ``, "Actor: Site admin", []string{}},
+		{"actor-name-case", `{"style": "title"}`, "Actor: Head of sales", []string{"Actor name 'Head of sales' should be in title case"}},
+		{"actor-name-case", `{"style": "title"}`, "Actor: Head of Sales", []string{}},
+		{"actor-name-case", `{"style": "title"}`, "Actor: ÉCOLE", []string{}},
+
+		{"goal-verb", ``, "Actor: A\n    Goals:\n        To publish\n        Publish", []string{"Goal 'To publish' should start with a verb"}},
+		{"goal-verb", `{"verbs": ["publish"]}`, "Actor: A\n    Goals:\n        Publish\n        Edit", []string{"Goal 'Edit' should start with a verb"}},
+
+		{"duplicate-goal", ``, "Actor: A\n    Goal: Edit\n    Goals:\n        edit", []string{"Goal 'edit' duplicates the goal on line 2"}},
+
+		{"empty-blurb", ``, "Actor: A\n    \"\"\"\n    Described\n    \"\"\"", []string{}},
+		{"empty-blurb", ``, "Actor: A\n    Goal: Edit", []string{"Actor 'A' has no description"}},
+
+		{"goal-length", `{"max": 4}`, "Actor: A\n    Goals:\n        Edit\n        Édité", []string{"Goal name is 5 characters long, more than 4"}},
+
+		{"known-tags", ``, "@anything\nActor: A", []string{}},
+		{"known-tags", `{"tags": ["@staff", "wip"]}`, "@staff @wip\nActor: A\n    @guest\n    Goals:\n        Edit\n        Read", []string{"Tag '@guest' is not a known tag"}},
+
+		{"single-use-tag", ``, "@once\nActor: A", []string{}},
+	} {
+
+		config := &Config{Rules: map[string]*RuleConfig{}}
+
+		for _, rule := range DefaultRules() {
+			config.Rules[rule.Name()] = &RuleConfig{Disabled: rule.Name() != test.rule}
+		}
+
+		if test.options != "" {
+			config.Rules[test.rule].Options = []byte(test.options)
+		}
+
+		linter, err := New(config)
+		assert.Nil(t, err)
+
+		problems, err := linter.Lint(parse(t, test.actor))
+		assert.Nil(t, err)
+
+		messages := make([]string, 0)
+
+		for _, p := range problems {
+			assert.Equal(t, test.rule, p.Rule)
+			messages = append(messages, p.Message)
+		}
+
+		assert.Equal(t, test.expected, messages, test.actor)
+	}
+}
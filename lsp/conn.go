@@ -0,0 +1,94 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// maxMessageLength is the longest message read, so that a bad header can't
+// make the server allocate without limit
+const maxMessageLength = 64 << 20
+
+// conn reads and writes JSON-RPC messages framed by Content-Length headers
+type conn struct {
+	reader *bufio.Reader
+	writer io.Writer
+	mutex  sync.Mutex
+}
+
+func newConn(r io.Reader, w io.Writer) *conn {
+	return &conn{reader: bufio.NewReader(r), writer: w}
+}
+
+func (c *conn) read() ([]byte, error) {
+
+	length := -1
+
+	for {
+		header, err := c.reader.ReadString('\n')
+
+		if err != nil {
+			return nil, err
+		}
+
+		header = strings.TrimRight(header, "\r\n")
+
+		// A blank line ends the headers
+		if header == "" {
+			break
+		}
+
+		parts := strings.SplitN(header, ":", 2)
+
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("Invalid header '%s'", header)
+		}
+
+		if strings.EqualFold(strings.TrimSpace(parts[0]), "Content-Length") {
+			if length, err = strconv.Atoi(strings.TrimSpace(parts[1])); err != nil || length < 0 {
+				return nil, fmt.Errorf("Invalid content length '%s'", strings.TrimSpace(parts[1]))
+			}
+
+			if length > maxMessageLength {
+				return nil, fmt.Errorf("Content length %d is longer than the limit of %d", length, maxMessageLength)
+			}
+		}
+	}
+
+	if length < 0 {
+		return nil, fmt.Errorf("Missing Content-Length header")
+	}
+
+	body := make([]byte, length)
+
+	if _, err := io.ReadFull(c.reader, body); err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+func (c *conn) write(message interface{}) error {
+
+	body, err := json.Marshal(message)
+
+	if err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, err := fmt.Fprintf(c.writer, "Content-Length: %d\r\n\r\n", len(body)); err != nil {
+		return err
+	}
+
+	_, err = c.writer.Write(body)
+
+	return err
+}
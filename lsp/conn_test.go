@@ -0,0 +1,48 @@
+package lsp
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ItReadsFramedMessages(t *testing.T) {
+
+	input := "Content-Length: 7\r\nContent-Type: application/vscode-jsonrpc\r\n\r\n{\"a\":1}content-length: 2\r\n\r\n{}"
+	c := newConn(bytes.NewBufferString(input), nil)
+
+	body, err := c.read()
+	assert.Nil(t, err)
+	assert.Equal(t, `{"a":1}`, string(body))
+
+	body, err = c.read()
+	assert.Nil(t, err)
+	assert.Equal(t, `{}`, string(body))
+}
+
+func Test_ItRejectsBadHeaders(t *testing.T) {
+
+	for _, input := range []string{
+		"Content-Length 7\r\n\r\n{\"a\":1}",
+		"Content-Length: seven\r\n\r\n{\"a\":1}",
+		"Content-Type: application/json\r\n\r\n{\"a\":1}",
+		"Content-Length: -7\r\n\r\n{\"a\":1}",
+		"Content-Length: 99999999999\r\n\r\n{\"a\":1}",
+	} {
+		_, err := newConn(bytes.NewBufferString(input), nil).read()
+		assert.NotNil(t, err, input)
+	}
+
+	_, err := newConn(bytes.NewBufferString("Content-Length: 67108865\r\n\r\n"), nil).read()
+	assert.Equal(t, fmt.Errorf("Content length 67108865 is longer than the limit of 67108864"), err)
+}
+
+func Test_ItWritesFramedMessages(t *testing.T) {
+
+	buf := &bytes.Buffer{}
+
+	assert.Nil(t, newConn(nil, buf).write(map[string]int{"a": 1}))
+	assert.Equal(t, "Content-Length: 7\r\n\r\n{\"a\":1}", buf.String())
+}
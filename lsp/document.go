@@ -0,0 +1,245 @@
+package lsp
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	gherkin "github.com/cucumber/gherkin-go"
+	"github.com/dryvercorp/actor"
+)
+
+// document is a file open in the editor. An .actor file is parsed again
+// after every change, reusing what it can of the previous parse.
+type document struct {
+	uri         string
+	path        string
+	options     actor.ParserOptions
+	version     int
+	text        string
+	lines       []string
+	source      *actor.Document
+	actor       *actor.Actor
+	diagnostics []*actor.Diagnostic
+	err         error
+}
+
+func newDocument(uri string, version int, text string, options actor.ParserOptions) *document {
+	d := &document{uri: uri, path: uriToPath(uri), options: options, version: version}
+	d.setText(text)
+	return d
+}
+
+func (d *document) isActor() bool {
+	return filepath.Ext(d.path) == ".actor"
+}
+
+func (d *document) setText(text string) {
+
+	d.text = text
+	d.lines = strings.Split(text, "\n")
+
+	if d.isActor() {
+		d.source = actor.NewDocument(text, d.options)
+		d.parsed()
+	}
+}
+
+// applyChange makes a change sent by the editor, to a range or, without one,
+// to the whole text
+func (d *document) applyChange(change textDocumentContentChangeEvent) error {
+
+	if change.Range == nil {
+		d.setText(change.Text)
+		return nil
+	}
+
+	if !d.isActor() {
+		d.text = d.splice(change)
+		d.lines = strings.Split(d.text, "\n")
+		return nil
+	}
+
+	// Apply also returns the error parsing the changed text, which is the
+	// document's own
+	_, err := d.source.Apply(actor.Edit{Start: d.location(change.Range.Start), End: d.location(change.Range.End), Text: change.Text})
+
+	var editErr *actor.EditError
+
+	if errors.As(err, &editErr) {
+
+		// The text has to stay the same as the editor's, so it's parsed again
+		// in full with the change made to it
+		d.setText(d.splice(change))
+
+		return fmt.Errorf("Apply change to %s: %s", d.path, editErr)
+	}
+
+	d.text = d.source.Text()
+	d.lines = strings.Split(d.text, "\n")
+	d.parsed()
+
+	return nil
+}
+
+// splice returns the text with a change made to its range
+func (d *document) splice(change textDocumentContentChangeEvent) string {
+
+	start, end := d.offset(change.Range.Start), d.offset(change.Range.End)
+
+	if end < start {
+		start, end = end, start
+	}
+
+	return d.text[:start] + change.Text + d.text[end:]
+}
+
+func (d *document) parsed() {
+	d.actor, d.err, d.diagnostics = d.source.Actor(), d.source.Err(), d.source.Diagnostics()
+}
+
+func (d *document) line(n int) string {
+
+	if n < 0 || n >= len(d.lines) {
+		return ""
+	}
+
+	return strings.TrimSuffix(d.lines[n], "\r")
+}
+
+// location converts an LSP position to a 1-based location, keeping it within
+// the text
+func (d *document) location(position Position) gherkin.Location {
+
+	if position.Line >= len(d.lines) {
+		position = Position{Line: len(d.lines) - 1, Character: utf16Length(d.line(len(d.lines) - 1))}
+	}
+
+	if position.Line < 0 {
+		position = Position{}
+	}
+
+	return gherkin.Location{Line: position.Line + 1, Column: d.column(position) + 1}
+}
+
+// offset converts an LSP position to a byte offset in the text
+func (d *document) offset(position Position) int {
+
+	location := d.location(position)
+	offset := 0
+
+	for _, l := range d.lines[:location.Line-1] {
+		offset += len(l) + 1
+	}
+
+	return offset + len(prefix(d.lines[location.Line-1], location.Column-1))
+}
+
+// position converts a 1-based, character counted location to an LSP position,
+// which is 0-based and counts UTF-16 code units
+func (d *document) position(location *gherkin.Location) Position {
+
+	line := d.line(location.Line - 1)
+	characters := location.Column - 1
+
+	if characters < 0 {
+		characters = 0
+	}
+
+	return Position{Line: location.Line - 1, Character: utf16Length(prefix(line, characters))}
+}
+
+// column converts an LSP position to a 0-based character offset in its line
+func (d *document) column(position Position) int {
+
+	units := 0
+	characters := 0
+
+	for _, r := range d.line(position.Line) {
+
+		if units >= position.Character {
+			break
+		}
+
+		units += len(utf16.Encode([]rune{r}))
+		characters++
+	}
+
+	return characters
+}
+
+// lineRange spans from a location to the end of its line
+func (d *document) lineRange(location *gherkin.Location) Range {
+
+	start := d.position(location)
+	end := Position{Line: start.Line, Character: utf16Length(d.line(start.Line))}
+
+	return Range{Start: start, End: end}
+}
+
+// nameRange spans a name starting at a location
+func (d *document) nameRange(location *gherkin.Location, name string) Range {
+
+	start := d.position(location)
+	end := Position{Line: start.Line, Character: start.Character + utf16Length(name)}
+
+	return Range{Start: start, End: end}
+}
+
+func (d *document) fullRange() Range {
+	last := len(d.lines) - 1
+	return Range{End: Position{Line: last, Character: utf16Length(d.line(last))}}
+}
+
+func prefix(s string, characters int) string {
+
+	for i := range s {
+
+		if characters == 0 {
+			return s[:i]
+		}
+
+		characters--
+	}
+
+	return s
+}
+
+func utf16Length(s string) int {
+
+	length := 0
+
+	for _, r := range s {
+		if utf8.ValidRune(r) && r >= 0x10000 {
+			length += 2
+		} else {
+			length++
+		}
+	}
+
+	return length
+}
+
+func uriToPath(uri string) string {
+
+	u, err := url.Parse(uri)
+
+	if err != nil || u.Scheme != "file" {
+		return uri
+	}
+
+	return filepath.FromSlash(u.Path)
+}
+
+func pathToURI(path string) string {
+
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
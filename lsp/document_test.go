@@ -0,0 +1,84 @@
+package lsp
+
+import (
+	"fmt"
+	"testing"
+
+	gherkin "github.com/cucumber/gherkin-go"
+	"github.com/dryvercorp/actor"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ItConvertsBetweenLocationsAndPositions(t *testing.T) {
+
+	d := newDocument("file:///tmp/emoji.actor", 1, "Actor: Emoji\r\n    | 😀 | é |\r\n", actor.ParserOptions{})
+
+	assert.NotNil(t, d.actor)
+	assert.Equal(t, 3, len(d.lines))
+	assert.Equal(t, "    | 😀 | é |", d.line(1))
+
+	// The emoji is two UTF-16 code units but one character
+	cell := d.actor.DataTable.Rows[0].Cells[1]
+	assert.Equal(t, &gherkin.Location{Line: 2, Column: 11}, cell.Location)
+	assert.Equal(t, Position{Line: 1, Character: 11}, d.position(cell.Location))
+	assert.Equal(t, 10, d.column(Position{Line: 1, Character: 11}))
+
+	assert.Equal(t, Range{Start: Position{Line: 0, Character: 0}, End: Position{Line: 0, Character: 12}}, d.lineRange(d.actor.Location))
+	assert.Equal(t, Range{End: Position{Line: 2, Character: 0}}, d.fullRange())
+}
+
+func Test_ItOnlyParsesActorFiles(t *testing.T) {
+
+	d := newDocument("file:///tmp/login.feature", 1, "Feature: Login\n", actor.ParserOptions{})
+
+	assert.False(t, d.isActor())
+	assert.Nil(t, d.actor)
+	assert.Nil(t, d.err)
+}
+
+func Test_ItConvertsBetweenURIsAndPaths(t *testing.T) {
+
+	assert.Equal(t, "/tmp/my actors/admin.actor", uriToPath("file:///tmp/my%20actors/admin.actor"))
+	assert.Equal(t, "file:///tmp/my%20actors/admin.actor", pathToURI("/tmp/my actors/admin.actor"))
+	assert.Equal(t, "untitled:1", uriToPath("untitled:1"))
+}
+
+func Test_ItAppliesChanges(t *testing.T) {
+
+	change := func(startLine, startCharacter, endLine, endCharacter int, text string) textDocumentContentChangeEvent {
+		return textDocumentContentChangeEvent{
+			Range: &Range{Start: Position{Line: startLine, Character: startCharacter}, End: Position{Line: endLine, Character: endCharacter}},
+			Text:  text,
+		}
+	}
+
+	for _, uri := range []string{"file:///tmp/emoji.actor", "file:///tmp/emoji.feature"} {
+
+		d := newDocument(uri, 1, "Actor: 😀 Emoji\n    Goal: Smile\n", actor.ParserOptions{})
+
+		assert.Nil(t, d.applyChange(change(0, 10, 0, 15, "Faces")))
+		assert.Nil(t, d.applyChange(change(1, 10, 1, 15, "Frown\n    Goal: Laugh")))
+		assert.Nil(t, d.applyChange(change(5, 0, 5, 0, "\n")))
+
+		assert.Equal(t, "Actor: 😀 Faces\n    Goal: Frown\n    Goal: Laugh\n\n", d.text, uri)
+		assert.Equal(t, 5, len(d.lines), uri)
+	}
+
+	d := newDocument("file:///tmp/a.actor", 1, "Actor: A\n", actor.ParserOptions{})
+	assert.Nil(t, d.applyChange(change(0, 7, 0, 8, "B")))
+	assert.Equal(t, "B", d.actor.Name)
+
+	assert.Nil(t, d.applyChange(textDocumentContentChangeEvent{Text: "Actor: C\n"}))
+	assert.Equal(t, "C", d.actor.Name)
+
+	// A parse error isn't an error applying the change
+	assert.Nil(t, d.applyChange(change(0, 0, 0, 8, "Actor:")))
+	assert.NotNil(t, d.err)
+
+	// A change the document refuses is made to the text, which is parsed again
+	d.applyChange(textDocumentContentChangeEvent{Text: "Actor: C\n"})
+	assert.Equal(t, fmt.Errorf("Apply change to /tmp/a.actor: Edit 1: End is before the start"), d.applyChange(change(0, 8, 0, 7, "D")))
+	assert.Equal(t, "Actor: D\n", d.text)
+	assert.Equal(t, "D", d.actor.Name)
+	assert.Nil(t, d.err)
+}
@@ -0,0 +1,340 @@
+package lsp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	gherkin "github.com/cucumber/gherkin-go"
+	"github.com/dryvercorp/actor"
+	"github.com/dryvercorp/actor/narrative"
+)
+
+func (s *Server) documentSymbol(params json.RawMessage) (interface{}, error) {
+
+	var p documentSymbolParams
+
+	if err := unmarshalParams(params, &p); err != nil {
+		return nil, err
+	}
+
+	d, err := s.document(p.TextDocument.URI)
+
+	if err != nil {
+		return nil, err
+	}
+
+	symbols := make([]*DocumentSymbol, 0)
+
+	if d.actor == nil {
+		return symbols, nil
+	}
+
+	symbol := &DocumentSymbol{
+		Name:           d.actor.Name,
+		Kind:           symbolClass,
+		Range:          Range{Start: d.position(d.actor.Location), End: d.fullRange().End},
+		SelectionRange: d.nameRange(d.actor.NameLocation, d.actor.Name),
+		Children:       make([]*DocumentSymbol, 0, len(d.actor.Goals)),
+	}
+
+	for _, goal := range d.actor.Goals {
+		symbol.Children = append(symbol.Children, &DocumentSymbol{
+			Name:           goal.Name,
+			Kind:           symbolMethod,
+			Range:          d.lineRange(goal.Location),
+			SelectionRange: d.nameRange(goal.NameLocation, goal.Name),
+		})
+	}
+
+	return append(symbols, symbol), nil
+}
+
+func (s *Server) formatting(params json.RawMessage) (interface{}, error) {
+
+	var p documentFormattingParams
+
+	if err := unmarshalParams(params, &p); err != nil {
+		return nil, err
+	}
+
+	d, err := s.document(p.TextDocument.URI)
+
+	if err != nil {
+		return nil, err
+	}
+
+	edits := make([]*TextEdit, 0)
+
+	if d.actor == nil {
+		return edits, nil
+	}
+
+	// Writing the actor would lose its comments
+	if len(d.actor.Comments) > 0 {
+		s.notify("window/showMessage", &showMessageParams{Type: messageInfo, Message: "Files with comments aren't formatted, as the comments would be lost"})
+		return edits, nil
+	}
+
+	buf := bytes.NewBuffer(nil)
+
+	if err := d.actor.Write(buf); err != nil {
+		return nil, err
+	}
+
+	if buf.String() == d.text {
+		return edits, nil
+	}
+
+	return append(edits, &TextEdit{Range: d.fullRange(), NewText: buf.String()}), nil
+}
+
+func (s *Server) hover(params json.RawMessage) (interface{}, error) {
+
+	var p textDocumentPositionParams
+
+	if err := unmarshalParams(params, &p); err != nil {
+		return nil, err
+	}
+
+	d, err := s.document(p.TextDocument.URI)
+
+	if err != nil {
+		return nil, err
+	}
+
+	word, start, end := wordAt(d.line(p.Position.Line), d.column(p.Position))
+
+	if !strings.HasPrefix(word, "@") || len(word) == 1 {
+		return nil, nil
+	}
+
+	tag := word[1:]
+	users := make([]string, 0)
+	goals := 0
+
+	for _, a := range s.workspace().Actors() {
+
+		if a.HasTag(tag) {
+			users = append(users, a.Name)
+		}
+
+		for _, goal := range a.Goals {
+			if goal.HasTag(tag) {
+				goals++
+			}
+		}
+	}
+
+	text := fmt.Sprintf("**@%s**\n\n", tag)
+
+	if s.options.Tags != nil {
+		text += describeTag(s.options.Tags.Lookup(tag))
+	}
+
+	text += fmt.Sprintf("Tagged on %s and %s", plural(len(users), "actor"), plural(goals, "goal"))
+
+	if len(users) > 0 {
+		text += ": " + strings.Join(users, ", ")
+	}
+
+	line := d.line(p.Position.Line)
+
+	return &Hover{
+		Contents: markupContent{Kind: "markdown", Value: text},
+		Range: &Range{
+			Start: Position{Line: p.Position.Line, Character: utf16Length(prefix(line, start))},
+			End:   Position{Line: p.Position.Line, Character: utf16Length(prefix(line, end))},
+		},
+	}, nil
+}
+
+func (s *Server) completion(params json.RawMessage) (interface{}, error) {
+
+	var p textDocumentPositionParams
+
+	if err := unmarshalParams(params, &p); err != nil {
+		return nil, err
+	}
+
+	d, err := s.document(p.TextDocument.URI)
+
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*CompletionItem, 0)
+	typed := strings.TrimLeft(prefix(d.line(p.Position.Line), d.column(p.Position)), " \t")
+
+	if word := typed[strings.LastIndexAny(typed, " \t")+1:]; strings.HasPrefix(word, "@") {
+
+		for _, tag := range s.tags() {
+			items = append(items, &CompletionItem{Label: "@" + tag, Kind: completionValue, InsertText: tag})
+		}
+
+		return items, nil
+	}
+
+	// Keywords only start a line
+	if !d.isActor() || strings.ContainsAny(typed, " \t:#|") {
+		return items, nil
+	}
+
+	language := ""
+
+	if d.actor != nil {
+		language = d.actor.Language
+	}
+
+	keywords, err := actor.Keywords(language)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, keyword := range keywords {
+		items = append(items, &CompletionItem{Label: keyword, Kind: completionKeyword, InsertText: keyword + ": "})
+	}
+
+	return items, nil
+}
+
+func (s *Server) definition(params json.RawMessage) (interface{}, error) {
+
+	var p textDocumentPositionParams
+
+	if err := unmarshalParams(params, &p); err != nil {
+		return nil, err
+	}
+
+	d, err := s.document(p.TextDocument.URI)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var file *actor.ProjectFile
+
+	// A feature names its actor in its narrative, or with a tag such as
+	// @site-admin
+	if role, ok := narrative.Role(d.line(p.Position.Line)); ok {
+		file = s.workspace().FindActor(role)
+	} else if word, _, _ := wordAt(d.line(p.Position.Line), d.column(p.Position)); strings.HasPrefix(word, "@") && !d.isActor() {
+		file = s.workspace().FindActorByTag(word)
+	}
+
+	if file == nil {
+		return nil, nil
+	}
+
+	uri := pathToURI(file.Path)
+	position := Position{Line: file.Actor.Location.Line - 1, Character: file.Actor.Location.Column - 1}
+
+	if target, ok := s.documents[uri]; ok {
+		position = target.position(file.Actor.Location)
+	}
+
+	return &Location{URI: uri, Range: Range{Start: position, End: position}}, nil
+}
+
+// tags returns every tag registered or used in the workspace, other than
+// deprecated ones, sorted
+func (s *Server) tags() []string {
+
+	seen := make(map[string]bool)
+
+	used := func(name string) {
+		if _, ok := seen[name]; !ok {
+			seen[name] = true
+		}
+	}
+
+	if s.options.Tags != nil {
+		for _, tag := range s.options.Tags.Tags {
+			seen[tag.Name] = !tag.Deprecated
+		}
+	}
+
+	for _, a := range s.workspace().Actors() {
+		actor.Inspect(a, func(node actor.Node) bool {
+
+			if tag, ok := node.(*gherkin.Tag); ok {
+				used(tag.Name)
+			}
+
+			return true
+		})
+	}
+
+	tags := make([]string, 0, len(seen))
+
+	for tag, use := range seen {
+		if use {
+			tags = append(tags, tag)
+		}
+	}
+
+	sort.Strings(tags)
+
+	return tags
+}
+
+// describeTag is the hover text for a tag from the registry
+func describeTag(definition *actor.TagDefinition) string {
+
+	if definition == nil {
+		return "*Not a registered tag*\n\n"
+	}
+
+	text := ""
+
+	if definition.Description != "" {
+		text += definition.Description + "\n\n"
+	}
+
+	if definition.Deprecated && definition.ReplacedBy != "" {
+		text += fmt.Sprintf("*Deprecated: use @%s instead*\n\n", definition.ReplacedBy)
+	} else if definition.Deprecated {
+		text += "*Deprecated*\n\n"
+	}
+
+	return text
+}
+
+func plural(n int, noun string) string {
+
+	if n == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// wordAt returns the tag or word around a character offset in a line, and
+// the character offsets of its start and end
+func wordAt(line string, column int) (string, int, int) {
+
+	runes := []rune(line)
+
+	isWord := func(r rune) bool {
+		return r == '@' || r == '_' || r == '-' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+	}
+
+	if column > len(runes) {
+		column = len(runes)
+	}
+
+	start, end := column, column
+
+	for start > 0 && isWord(runes[start-1]) {
+		start--
+	}
+
+	for end < len(runes) && isWord(runes[end]) {
+		end++
+	}
+
+	return string(runes[start:end]), start, end
+}
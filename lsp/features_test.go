@@ -0,0 +1,202 @@
+package lsp
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testActor = "@admin\nActor: Administrator\n    @moderation\n    Goal: Moderate comments\n    Goals:\n        Publish articles\n"
+
+func Test_ItListsTheActorAndGoalsAsSymbols(t *testing.T) {
+
+	uri := "file:///tmp/admin.actor"
+
+	messages, _ := converse(t,
+		open(uri, testActor),
+		call(1, "textDocument/documentSymbol", &documentSymbolParams{TextDocument: textDocumentIdentifier{URI: uri}}),
+	)
+
+	var symbols []*DocumentSymbol
+	result(t, messages, 1, &symbols)
+
+	assert.Equal(t, 1, len(symbols))
+	assert.Equal(t, "Administrator", symbols[0].Name)
+	assert.Equal(t, symbolClass, symbols[0].Kind)
+	assert.Equal(t, Range{Start: Position{Line: 1, Character: 7}, End: Position{Line: 1, Character: 20}}, symbols[0].SelectionRange)
+
+	assert.Equal(t, 2, len(symbols[0].Children))
+	assert.Equal(t, "Moderate comments", symbols[0].Children[0].Name)
+	assert.Equal(t, Range{Start: Position{Line: 3, Character: 10}, End: Position{Line: 3, Character: 27}}, symbols[0].Children[0].SelectionRange)
+	assert.Equal(t, "Publish articles", symbols[0].Children[1].Name)
+	assert.Equal(t, symbolMethod, symbols[0].Children[1].Kind)
+}
+
+func Test_ItFormatsWithTheWriter(t *testing.T) {
+
+	formatting := func(uri string) *request {
+		return call(1, "textDocument/formatting", &documentFormattingParams{TextDocument: textDocumentIdentifier{URI: uri}})
+	}
+
+	messages, _ := converse(t, open("file:///tmp/a.actor", "Actor:   Messy\n  Goal: Tidy up\n"), formatting("file:///tmp/a.actor"))
+
+	var edits []*TextEdit
+	result(t, messages, 1, &edits)
+
+	assert.Equal(t, []*TextEdit{
+		{Range: Range{End: Position{Line: 2}}, NewText: "\nActor: Messy\n    Goals:\n        Tidy up\n"},
+	}, edits)
+
+	// Comments would be lost, so aren't formatted
+	messages, _ = converse(t, open("file:///tmp/b.actor", "Actor:   Messy # Keep me\n"), formatting("file:///tmp/b.actor"))
+
+	result(t, messages, 1, &edits)
+	assert.Equal(t, 0, len(edits))
+	assert.Equal(t, 1, len(notifications(messages, "window/showMessage")))
+}
+
+func Test_ItDescribesTagsOnHover(t *testing.T) {
+
+	uri := "file:///tmp/admin.actor"
+
+	messages, _ := converse(t,
+		open(uri, testActor),
+		open("file:///tmp/editor.actor", "@admin\nActor: Editor\n"),
+		call(1, "textDocument/hover", at(uri, 0, 3)),
+		call(2, "textDocument/hover", at(uri, 2, 6)),
+		call(3, "textDocument/hover", at(uri, 1, 3)),
+	)
+
+	var hover *Hover
+
+	result(t, messages, 1, &hover)
+	assert.Equal(t, "**@admin**\n\nTagged on 2 actors and 0 goals: Administrator, Editor", hover.Contents.Value)
+	assert.Equal(t, &Range{End: Position{Character: 6}}, hover.Range)
+
+	result(t, messages, 2, &hover)
+	assert.Equal(t, "**@moderation**\n\nTagged on 0 actors and 1 goal", hover.Contents.Value)
+
+	hover = nil
+	result(t, messages, 3, &hover)
+	assert.Nil(t, hover)
+}
+
+func Test_ItCompletesKeywordsAndTags(t *testing.T) {
+
+	uri := "file:///tmp/admin.actor"
+	fr := "file:///tmp/admin.fr.actor"
+
+	messages, _ := converse(t,
+		open(uri, testActor+"    @mod\n    Go\n"),
+		open(fr, "# language: fr\nActeur: Administrateur\n    Obj\n"),
+		call(1, "textDocument/completion", at(uri, 6, 8)),
+		call(2, "textDocument/completion", at(uri, 7, 6)),
+		call(3, "textDocument/completion", at(fr, 2, 7)),
+		call(4, "textDocument/completion", at(uri, 3, 15)),
+	)
+
+	var items []*CompletionItem
+
+	result(t, messages, 1, &items)
+	assert.Equal(t, []*CompletionItem{
+		{Label: "@admin", Kind: completionValue, InsertText: "admin"},
+		{Label: "@moderation", Kind: completionValue, InsertText: "moderation"},
+	}, items)
+
+	result(t, messages, 2, &items)
+	assert.Equal(t, 4, len(items))
+	assert.Equal(t, &CompletionItem{Label: "Actor", Kind: completionKeyword, InsertText: "Actor: "}, items[0])
+	assert.Equal(t, &CompletionItem{Label: "Aliases", Kind: completionKeyword, InsertText: "Aliases: "}, items[3])
+
+	result(t, messages, 3, &items)
+	assert.Equal(t, "Acteur", items[0].Label)
+	assert.Equal(t, "Objectifs", items[2].Label)
+
+	result(t, messages, 4, &items)
+	assert.Equal(t, 0, len(items))
+}
+
+func Test_ItGoesToTheActorOfAFeature(t *testing.T) {
+
+	dir := newTestWorkspace(t, map[string]string{
+		"actors/admin.actor": "# Site staff\nActor: Site Administrator\n    Aliases:\n        Site admin\n",
+	})
+
+	feature := pathToURI(filepath.Join(dir, "features/moderation.feature"))
+	tagged := pathToURI(filepath.Join(dir, "features/spam.feature"))
+
+	messages, _ := converse(t,
+		call(1, "initialize", &initializeParams{RootURI: pathToURI(dir)}),
+		open(feature, "Feature: Moderation\n  As a site  administrator,\n  I want to moderate comments\n"),
+		open(tagged, "@site-admin @spam\nFeature: Spam\n  As a site admin\n"),
+		call(2, "textDocument/definition", at(feature, 1, 5)),
+		call(3, "textDocument/definition", at(feature, 2, 5)),
+		call(4, "textDocument/definition", at(tagged, 0, 3)),
+		call(5, "textDocument/definition", at(tagged, 2, 5)),
+		call(6, "textDocument/definition", at(tagged, 0, 14)),
+	)
+
+	var location *Location
+
+	result(t, messages, 2, &location)
+	assert.Equal(t, &Location{
+		URI:   pathToURI(filepath.Join(dir, "actors/admin.actor")),
+		Range: Range{Start: Position{Line: 1}, End: Position{Line: 1}},
+	}, location)
+
+	location = nil
+	result(t, messages, 3, &location)
+	assert.Nil(t, location)
+
+	for _, id := range []int{4, 5} {
+		location = nil
+		result(t, messages, id, &location)
+		assert.Equal(t, pathToURI(filepath.Join(dir, "actors/admin.actor")), location.URI)
+	}
+
+	location = nil
+	result(t, messages, 6, &location)
+	assert.Nil(t, location)
+}
+
+func Test_ItUsesTheTagRegistry(t *testing.T) {
+
+	dir := newTestWorkspace(t, map[string]string{
+		".actor-tags.json": `{"tags": [{"name": "admin", "description": "Has full access"}, {"name": "wip", "deprecated": true, "replacedBy": "draft"}, {"name": "draft"}]}`,
+	})
+
+	uri := pathToURI(filepath.Join(dir, "admin.actor"))
+
+	messages, _ := converse(t,
+		call(1, "initialize", &initializeParams{RootURI: pathToURI(dir)}),
+		open(uri, "@admin @wip\nActor: Admin\n"),
+		open("file:///tmp/login.feature", "@\nFeature: Login\n"),
+		call(2, "textDocument/hover", at(uri, 0, 2)),
+		call(3, "textDocument/hover", at(uri, 0, 9)),
+		call(4, "textDocument/completion", at("file:///tmp/login.feature", 0, 1)),
+	)
+
+	var hover *Hover
+
+	result(t, messages, 2, &hover)
+	assert.Equal(t, "**@admin**\n\nHas full access\n\nTagged on 1 actor and 0 goals: Admin", hover.Contents.Value)
+
+	result(t, messages, 3, &hover)
+	assert.Equal(t, "**@wip**\n\n*Deprecated: use @draft instead*\n\nTagged on 1 actor and 0 goals: Admin", hover.Contents.Value)
+
+	var items []*CompletionItem
+
+	result(t, messages, 4, &items)
+	assert.Equal(t, []*CompletionItem{
+		{Label: "@admin", Kind: completionValue, InsertText: "admin"},
+		{Label: "@draft", Kind: completionValue, InsertText: "draft"},
+	}, items)
+
+	var params publishDiagnosticsParams
+
+	published := notifications(messages, "textDocument/publishDiagnostics")
+	assert.Nil(t, json.Unmarshal(published[0].Params, &params))
+	assert.Equal(t, "Tag '@wip' is deprecated, use '@draft' instead", params.Diagnostics[0].Message)
+}
@@ -0,0 +1,349 @@
+// Package lsp implements a Language Server Protocol server for .actor files,
+// with go-to-definition from the narratives of .feature files.
+package lsp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"sort"
+
+	"github.com/dryvercorp/actor"
+)
+
+var ErrExitWithoutShutdown = fmt.Errorf("Exit notification received before shutdown")
+
+type handler func(params json.RawMessage) (interface{}, error)
+
+type Server struct {
+	conn      *conn
+	logger    *log.Logger
+	root      string
+	options   actor.ParserOptions
+	project   *actor.Project
+	documents map[string]*document
+	shutdown  bool
+	handlers  map[string]handler
+}
+
+func NewServer(r io.Reader, w io.Writer) *Server {
+
+	s := &Server{
+		conn:      newConn(r, w),
+		logger:    log.New(ioutil.Discard, "", 0),
+		project:   &actor.Project{},
+		documents: make(map[string]*document),
+	}
+
+	s.handlers = map[string]handler{
+		"initialize":                  s.initialize,
+		"initialized":                 s.ignore,
+		"shutdown":                    s.shutdownRequest,
+		"textDocument/didOpen":        s.didOpen,
+		"textDocument/didChange":      s.didChange,
+		"textDocument/didClose":       s.didClose,
+		"textDocument/didSave":        s.didSave,
+		"textDocument/documentSymbol": s.documentSymbol,
+		"textDocument/formatting":     s.formatting,
+		"textDocument/hover":          s.hover,
+		"textDocument/completion":     s.completion,
+		"textDocument/definition":     s.definition,
+	}
+
+	return s
+}
+
+// SetLogger sets where problems that can't be sent to the client are logged
+func (s *Server) SetLogger(logger *log.Logger) {
+	s.logger = logger
+}
+
+// Serve handles messages until the client sends the exit notification
+func (s *Server) Serve() error {
+
+	for {
+		body, err := s.conn.read()
+
+		if err != nil {
+			return err
+		}
+
+		var req request
+
+		if err := json.Unmarshal(body, &req); err != nil {
+			s.reply(nil, nil, &responseError{Code: codeParseError, Message: err.Error()})
+			continue
+		}
+
+		if req.Method == "exit" {
+			if !s.shutdown {
+				return ErrExitWithoutShutdown
+			}
+
+			return nil
+		}
+
+		s.handle(&req)
+	}
+}
+
+func (s *Server) handle(req *request) {
+
+	h, ok := s.handlers[req.Method]
+
+	if !ok {
+		// Notifications that aren't understood are ignored
+		if req.ID != nil {
+			s.reply(req.ID, nil, &responseError{Code: codeMethodNotFound, Message: fmt.Sprintf("Method '%s' not found", req.Method)})
+		}
+
+		return
+	}
+
+	if s.shutdown && req.ID != nil {
+		s.reply(req.ID, nil, &responseError{Code: codeInvalidRequest, Message: "Server is shutting down"})
+		return
+	}
+
+	result, err := h(req.Params)
+
+	if req.ID != nil {
+		s.reply(req.ID, result, err)
+	} else if err != nil {
+		s.logger.Printf("%s: %s", req.Method, err)
+	}
+}
+
+func (s *Server) reply(id *json.RawMessage, result interface{}, err error) {
+
+	var message interface{} = &response{JSONRPC: "2.0", ID: id, Result: result}
+
+	if err != nil {
+		e, ok := err.(*responseError)
+
+		if !ok {
+			e = &responseError{Code: codeInternalError, Message: err.Error()}
+		}
+
+		message = &errorResponse{JSONRPC: "2.0", ID: id, Error: e}
+	}
+
+	if err := s.conn.write(message); err != nil {
+		s.logger.Printf("Write response: %s", err)
+	}
+}
+
+func (s *Server) notify(method string, params interface{}) {
+	if err := s.conn.write(&notification{JSONRPC: "2.0", Method: method, Params: params}); err != nil {
+		s.logger.Printf("Write %s: %s", method, err)
+	}
+}
+
+func unmarshalParams(params json.RawMessage, v interface{}) error {
+
+	if err := json.Unmarshal(params, v); err != nil {
+		return &responseError{Code: codeInvalidParams, Message: err.Error()}
+	}
+
+	return nil
+}
+
+func (s *Server) ignore(params json.RawMessage) (interface{}, error) {
+	return nil, nil
+}
+
+func (s *Server) initialize(params json.RawMessage) (interface{}, error) {
+
+	var p initializeParams
+
+	if err := unmarshalParams(params, &p); err != nil {
+		return nil, err
+	}
+
+	s.root = p.RootPath
+
+	if p.RootURI != "" {
+		s.root = uriToPath(p.RootURI)
+	}
+
+	if s.root != "" {
+		project, err := actor.LoadProject(s.root, s.options)
+
+		if err != nil {
+			s.logger.Printf("Load project: %s", err)
+		} else {
+			s.project = project
+			s.options.Tags = project.Tags
+		}
+	}
+
+	return &initializeResult{
+		Capabilities: serverCapabilities{
+			TextDocumentSync:           textDocumentSyncOptions{OpenClose: true, Change: syncIncremental, Save: true},
+			DocumentSymbolProvider:     true,
+			DocumentFormattingProvider: true,
+			HoverProvider:              true,
+			CompletionProvider:         completionOptions{TriggerCharacters: []string{"@"}},
+			DefinitionProvider:         true,
+		},
+		ServerInfo: serverInfo{Name: "actor-lsp"},
+	}, nil
+}
+
+func (s *Server) shutdownRequest(params json.RawMessage) (interface{}, error) {
+	s.shutdown = true
+	return nil, nil
+}
+
+func (s *Server) didOpen(params json.RawMessage) (interface{}, error) {
+
+	var p didOpenTextDocumentParams
+
+	if err := unmarshalParams(params, &p); err != nil {
+		return nil, err
+	}
+
+	d := newDocument(p.TextDocument.URI, p.TextDocument.Version, p.TextDocument.Text, s.options)
+	s.documents[d.uri] = d
+	s.publishDiagnostics(d)
+
+	return nil, nil
+}
+
+func (s *Server) didChange(params json.RawMessage) (interface{}, error) {
+
+	var p didChangeTextDocumentParams
+
+	if err := unmarshalParams(params, &p); err != nil {
+		return nil, err
+	}
+
+	d, ok := s.documents[p.TextDocument.URI]
+
+	if !ok {
+		return nil, nil
+	}
+
+	d.version = p.TextDocument.Version
+
+	for _, change := range p.ContentChanges {
+		if err := d.applyChange(change); err != nil {
+			s.logger.Printf("%s", err)
+		}
+	}
+
+	s.publishDiagnostics(d)
+
+	return nil, nil
+}
+
+func (s *Server) didClose(params json.RawMessage) (interface{}, error) {
+
+	var p didCloseTextDocumentParams
+
+	if err := unmarshalParams(params, &p); err != nil {
+		return nil, err
+	}
+
+	if d, ok := s.documents[p.TextDocument.URI]; ok && d.isActor() {
+		s.notify("textDocument/publishDiagnostics", &publishDiagnosticsParams{URI: d.uri, Diagnostics: []*Diagnostic{}})
+	}
+
+	delete(s.documents, p.TextDocument.URI)
+
+	return nil, nil
+}
+
+func (s *Server) didSave(params json.RawMessage) (interface{}, error) {
+
+	var p didSaveTextDocumentParams
+
+	if err := unmarshalParams(params, &p); err != nil {
+		return nil, err
+	}
+
+	path := uriToPath(p.TextDocument.URI)
+
+	if filepath.Ext(path) != ".actor" {
+		return nil, nil
+	}
+
+	file := actor.LoadProjectFile(path, s.options)
+
+	for i, f := range s.project.Files {
+		if f.Path == path {
+			s.project.Files[i] = file
+			return nil, nil
+		}
+	}
+
+	s.project.Files = append(s.project.Files, file)
+
+	return nil, nil
+}
+
+func (s *Server) publishDiagnostics(d *document) {
+
+	if !d.isActor() {
+		return
+	}
+
+	diagnostics := make([]*Diagnostic, 0, len(d.diagnostics))
+
+	for _, diagnostic := range d.diagnostics {
+
+		severity := severityError
+
+		if diagnostic.Severity == actor.SeverityWarning {
+			severity = severityWarning
+		}
+
+		diagnostics = append(diagnostics, &Diagnostic{
+			Range:    d.lineRange(diagnostic.Location),
+			Severity: severity,
+			Source:   "actor",
+			Message:  diagnostic.Message,
+		})
+	}
+
+	s.notify("textDocument/publishDiagnostics", &publishDiagnosticsParams{URI: d.uri, Version: d.version, Diagnostics: diagnostics})
+}
+
+func (s *Server) document(uri string) (*document, error) {
+
+	d, ok := s.documents[uri]
+
+	if !ok {
+		return nil, &responseError{Code: codeInvalidParams, Message: fmt.Sprintf("Document '%s' is not open", uri)}
+	}
+
+	return d, nil
+}
+
+// workspace is the project as the editor sees it, with open documents in
+// place of the saved files
+func (s *Server) workspace() *actor.Project {
+
+	workspace := &actor.Project{Root: s.root, Files: make([]*actor.ProjectFile, 0)}
+	open := make(map[string]bool)
+
+	for _, d := range s.documents {
+		if d.isActor() {
+			workspace.Files = append(workspace.Files, &actor.ProjectFile{Path: d.path, Actor: d.actor, Diagnostics: d.diagnostics, Err: d.err})
+			open[d.path] = true
+		}
+	}
+
+	for _, f := range s.project.Files {
+		if !open[f.Path] {
+			workspace.Files = append(workspace.Files, f)
+		}
+	}
+
+	sort.Slice(workspace.Files, func(i, j int) bool { return workspace.Files[i].Path < workspace.Files[j].Path })
+
+	return workspace
+}
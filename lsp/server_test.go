@@ -0,0 +1,200 @@
+package lsp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testMessage struct {
+	ID     *int            `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  *responseError  `json:"error"`
+}
+
+func call(id int, method string, params interface{}) *request {
+
+	r := notify(method, params)
+	raw := json.RawMessage(fmt.Sprint(id))
+	r.ID = &raw
+
+	return r
+}
+
+func notify(method string, params interface{}) *request {
+
+	body, _ := json.Marshal(params)
+
+	return &request{JSONRPC: "2.0", Method: method, Params: body}
+}
+
+// converse sends the requests to a new server, and returns its error along
+// with everything it sent back
+func converse(t *testing.T, requests ...*request) ([]*testMessage, error) {
+
+	in, out := &bytes.Buffer{}, &bytes.Buffer{}
+
+	for _, r := range requests {
+		assert.Nil(t, newConn(nil, in).write(r))
+	}
+
+	err := NewServer(in, out).Serve()
+
+	messages := make([]*testMessage, 0)
+	c := newConn(out, nil)
+
+	for {
+		body, readErr := c.read()
+
+		if readErr == io.EOF {
+			break
+		}
+
+		assert.Nil(t, readErr)
+
+		message := &testMessage{}
+		assert.Nil(t, json.Unmarshal(body, message))
+
+		messages = append(messages, message)
+	}
+
+	return messages, err
+}
+
+func result(t *testing.T, messages []*testMessage, id int, v interface{}) {
+
+	for _, m := range messages {
+		if m.ID != nil && *m.ID == id {
+			assert.Nil(t, m.Error)
+			assert.Nil(t, json.Unmarshal(m.Result, v))
+			return
+		}
+	}
+
+	t.Fatalf("No response to request %d", id)
+}
+
+func notifications(messages []*testMessage, method string) []*testMessage {
+
+	found := make([]*testMessage, 0)
+
+	for _, m := range messages {
+		if m.ID == nil && m.Method == method {
+			found = append(found, m)
+		}
+	}
+
+	return found
+}
+
+func open(uri, text string) *request {
+	return notify("textDocument/didOpen", &didOpenTextDocumentParams{TextDocument: textDocumentItem{URI: uri, Version: 1, Text: text}})
+}
+
+func at(uri string, line, character int) *textDocumentPositionParams {
+	return &textDocumentPositionParams{TextDocument: textDocumentIdentifier{URI: uri}, Position: Position{Line: line, Character: character}}
+}
+
+func newTestWorkspace(t *testing.T, files map[string]string) string {
+
+	dir := t.TempDir()
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		assert.Nil(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.Nil(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+
+	return dir
+}
+
+func Test_ItInitialisesAndShutsDown(t *testing.T) {
+
+	messages, err := converse(t,
+		call(1, "initialize", &initializeParams{}),
+		notify("initialized", struct{}{}),
+		call(2, "shutdown", nil),
+		notify("exit", nil),
+	)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(messages))
+
+	var initialized initializeResult
+	result(t, messages, 1, &initialized)
+
+	assert.Equal(t, "actor-lsp", initialized.ServerInfo.Name)
+	assert.Equal(t, syncIncremental, initialized.Capabilities.TextDocumentSync.Change)
+	assert.True(t, initialized.Capabilities.DocumentSymbolProvider)
+	assert.Equal(t, []string{"@"}, initialized.Capabilities.CompletionProvider.TriggerCharacters)
+}
+
+func Test_ItFailsToExitWithoutShutdown(t *testing.T) {
+
+	_, err := converse(t, notify("exit", nil))
+	assert.Equal(t, ErrExitWithoutShutdown, err)
+}
+
+func Test_ItRejectsUnknownRequests(t *testing.T) {
+
+	messages, err := converse(t,
+		call(1, "workspace/unknown", nil),
+		notify("$/unknown", nil),
+		call(2, "shutdown", nil),
+		call(3, "textDocument/hover", at("file:///tmp/a.actor", 0, 0)),
+		notify("exit", nil),
+	)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(messages))
+	assert.Equal(t, codeMethodNotFound, messages[0].Error.Code)
+	assert.Equal(t, codeInvalidRequest, messages[2].Error.Code)
+}
+
+func Test_ItPublishesDiagnostics(t *testing.T) {
+
+	uri := "file:///tmp/bad.actor"
+
+	messages, _ := converse(t,
+		open(uri, "Actor: Bad\n    Motto: Be bad\n"),
+		notify("textDocument/didChange", &didChangeTextDocumentParams{
+			TextDocument: versionedTextDocumentIdentifier{URI: uri, Version: 2},
+			ContentChanges: []textDocumentContentChangeEvent{
+				{Range: &Range{Start: Position{Line: 1, Character: 4}, End: Position{Line: 1, Character: 17}}, Text: "Goal: Be good"},
+				{Range: &Range{Start: Position{Line: 0, Character: 7}, End: Position{Line: 0, Character: 10}}, Text: "Good"},
+			},
+		}),
+		notify("textDocument/didClose", &didCloseTextDocumentParams{TextDocument: textDocumentIdentifier{URI: uri}}),
+		open("file:///tmp/login.feature", "Feature: Login\n"),
+	)
+
+	published := notifications(messages, "textDocument/publishDiagnostics")
+	assert.Equal(t, 3, len(published))
+
+	var params publishDiagnosticsParams
+
+	assert.Nil(t, json.Unmarshal(published[0].Params, &params))
+	assert.Equal(t, uri, params.URI)
+	assert.Equal(t, []*Diagnostic{
+		{
+			Range:    Range{Start: Position{Line: 1, Character: 4}, End: Position{Line: 1, Character: 17}},
+			Severity: severityError,
+			Source:   "actor",
+			Message:  "Unrecognised keyword 'Motto'",
+		},
+	}, params.Diagnostics)
+
+	for _, p := range published[1:] {
+		assert.Nil(t, json.Unmarshal(p.Params, &params))
+		assert.Equal(t, 0, len(params.Diagnostics))
+	}
+}
@@ -0,0 +1,581 @@
+package actor
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	gherkin "github.com/cucumber/gherkin-go"
+)
+
+const (
+	conflictActor     = "actor"
+	conflictComments  = "comments"
+	conflictName      = "name"
+	conflictLanguage  = "language"
+	conflictBlurb     = "blurb"
+	conflictDocString = "doc string"
+	conflictDataTable = "data table"
+	conflictGoal      = "goal"
+)
+
+// Conflict is a part of an actor that both sides of a merge changed, in
+// different ways. Part is what it's about: the actor, its name, language,
+// blurb, doc string or data table, one of its goals, or its comments.
+type Conflict struct {
+	Part    string `json:"part"`
+	Goal    string `json:"goal,omitempty"`
+	Message string `json:"message"`
+
+	// Each side as it's written between the conflict markers
+	ours, theirs func(w *writer) error
+}
+
+func (c *Conflict) String() string {
+	return c.Message
+}
+
+// MergeResult is the actor merged from two versions of the same one, and the
+// conflicts that couldn't be resolved. Where there's a conflict the actor
+// has our side of it; Write writes both between conflict markers.
+type MergeResult struct {
+	Actor     *Actor
+	Conflicts []*Conflict
+
+	parts map[string]*Conflict
+	goals map[*Goal]*Conflict
+	whole *Conflict
+}
+
+// Merge merges the changes made to an actor by two sides, ours and theirs,
+// since their common base, by the actor's meaning rather than its text. A
+// change made by one side only is kept, as is a change both made the same.
+// Tags, aliases and goals are sets: each side's additions and removals are
+// kept, goals are matched by name wherever they are, as for Diff, and each
+// goal is merged in turn. It's only a conflict when both sides changed the
+// same part, such as the blurb, the doc string of a goal, or a goal one side
+// removed or renamed. Comments are merged as a whole and, as they can't be put
+// back in place, written at the top of the file. Any actor may be nil, for a
+// file that was added or removed.
+func Merge(base, ours, theirs *Actor) *MergeResult {
+
+	m := &MergeResult{
+		parts: make(map[string]*Conflict),
+		goals: make(map[*Goal]*Conflict),
+	}
+
+	switch {
+	case ours == nil && theirs == nil:
+
+	case ours == nil || theirs == nil:
+		m.mergeRemoved(base, ours, theirs)
+
+	default:
+		m.mergeActors(base, ours, theirs)
+	}
+
+	return m
+}
+
+func (m *MergeResult) mergeActors(base, ours, theirs *Actor) {
+
+	if base == nil {
+		base = &Actor{}
+	}
+
+	merged := &Actor{
+		Node:         ours.Node,
+		NameLocation: ours.NameLocation,
+		Tags:         mergeTags(base.Tags, ours.Tags, theirs.Tags),
+		Aliases:      mergeAliases(base.Aliases, ours.Aliases, theirs.Aliases),
+	}
+
+	m.Actor = merged
+
+	merged.Name = ours.Name
+
+	if fromTheirs, ok := choose(base.Name, ours.Name, theirs.Name); !ok {
+		m.addPart(conflictName, "Both sides renamed the actor",
+			func(w *writer) error { return w.writeKeyword(w.keyword(token_actorDefinition), ours.Name) },
+			func(w *writer) error { return w.writeKeyword(w.keyword(token_actorDefinition), theirs.Name) })
+	} else if fromTheirs {
+		merged.Name, merged.NameLocation = theirs.Name, theirs.NameLocation
+	}
+
+	merged.Language = language(ours)
+
+	if fromTheirs, ok := choose(language(base), language(ours), language(theirs)); !ok {
+		m.addPart(conflictLanguage, "Both sides changed the language",
+			func(w *writer) error { return w.writeComment("language: " + language(ours)) },
+			func(w *writer) error { return w.writeComment("language: " + language(theirs)) })
+	} else if fromTheirs {
+		merged.Language = language(theirs)
+	}
+
+	merged.Blurb = ours.Blurb
+
+	if fromTheirs, ok := choose(strings.Join(base.Blurb, "\n"), strings.Join(ours.Blurb, "\n"), strings.Join(theirs.Blurb, "\n")); !ok {
+		m.addPart(conflictBlurb, "Both sides changed the blurb",
+			func(w *writer) error { return w.writeBlurbs(ours.Blurb) },
+			func(w *writer) error { return w.writeBlurbs(theirs.Blurb) })
+	} else if fromTheirs {
+		merged.Blurb = theirs.Blurb
+	}
+
+	merged.DocString = ours.DocString
+
+	if fromTheirs, ok := choose(docStringText(base.DocString), docStringText(ours.DocString), docStringText(theirs.DocString)); !ok {
+		m.addPart(conflictDocString, "Both sides changed the doc string", docStringSide(ours.DocString), docStringSide(theirs.DocString))
+	} else if fromTheirs {
+		merged.DocString = theirs.DocString
+	}
+
+	merged.DataTable = ours.DataTable
+
+	if fromTheirs, ok := choose(tableText(base.DataTable), tableText(ours.DataTable), tableText(theirs.DataTable)); !ok {
+		m.addPart(conflictDataTable, "Both sides changed the data table", tableSide(ours.DataTable), tableSide(theirs.DataTable))
+	} else if fromTheirs {
+		merged.DataTable = theirs.DataTable
+	}
+
+	merged.Goals = m.mergeGoals(base.Goals, ours.Goals, theirs.Goals)
+
+	merged.Comments = ours.Comments
+
+	if fromTheirs, ok := choose(commentText(base.Comments), commentText(ours.Comments), commentText(theirs.Comments)); !ok {
+		m.addPart(conflictComments, "Both sides changed the comments", commentsSide(ours.Comments), commentsSide(theirs.Comments))
+	} else if fromTheirs {
+		merged.Comments = theirs.Comments
+	}
+}
+
+// mergeRemoved merges an actor one side removed: if the other side didn't
+// change it, it stays removed, otherwise the changed actor is kept, but in
+// conflict
+func (m *MergeResult) mergeRemoved(base, ours, theirs *Actor) {
+
+	kept, by, other := ours, "theirs", "ours"
+
+	if ours == nil {
+		kept, by, other = theirs, "ours", "theirs"
+	}
+
+	if base == nil {
+		m.Actor = kept
+		return
+	}
+
+	if len(Diff(base, kept)) == 0 {
+		return
+	}
+
+	m.Actor = kept
+	m.whole = &Conflict{
+		Part:    conflictActor,
+		Message: fmt.Sprintf("The actor was removed by %s and changed by %s", by, other),
+		ours:    actorSide(ours),
+		theirs:  actorSide(theirs),
+	}
+
+	m.Conflicts = append(m.Conflicts, m.whole)
+}
+
+func (m *MergeResult) addPart(part, message string, ours, theirs func(w *writer) error) {
+
+	c := &Conflict{Part: part, Message: message, ours: ours, theirs: theirs}
+
+	m.parts[part] = c
+	m.Conflicts = append(m.Conflicts, c)
+}
+
+func (m *MergeResult) addGoal(merged *Goal, message string, ours, theirs *Goal) {
+
+	c := &Conflict{Part: conflictGoal, Goal: merged.Name, Message: message, ours: goalSide(ours), theirs: goalSide(theirs)}
+
+	m.goals[merged] = c
+	m.Conflicts = append(m.Conflicts, c)
+}
+
+// mergeGoals keeps our goals, in our order, merged with theirs, then the
+// goals only they added
+func (m *MergeResult) mergeGoals(base, ours, theirs []*Goal) []*Goal {
+
+	merged := make([]*Goal, 0, len(ours))
+
+	oursBase, theirsBase := matchGoals(base, ours), matchGoals(base, theirs)
+	baseTheirs := make(map[*Goal]*Goal)
+	baseOurs := make(map[*Goal]*Goal)
+
+	for o, b := range oursBase {
+		baseOurs[b] = o
+	}
+
+	for t, b := range theirsBase {
+		baseTheirs[b] = t
+	}
+
+	// Goals both sides added are matched by name only, as neither was
+	// renamed
+	addedByTheirs := make(map[string]*Goal)
+
+	for _, t := range theirs {
+		if _, ok := theirsBase[t]; !ok {
+			addedByTheirs[normaliseName(t.Name)] = t
+		}
+	}
+
+	done := make(map[*Goal]bool)
+
+	for _, o := range ours {
+
+		b, inBase := oursBase[o]
+
+		if !inBase {
+			if t, ok := addedByTheirs[normaliseName(o.Name)]; ok && !done[t] {
+				merged = append(merged, m.mergeGoal(&Goal{}, o, t))
+				done[t] = true
+			} else {
+				merged = append(merged, o)
+			}
+
+			continue
+		}
+
+		t, inTheirs := baseTheirs[b]
+
+		if !inTheirs {
+			if goalChanged(b, o) {
+				merged = append(merged, o)
+				m.addGoal(o, fmt.Sprintf("Goal '%s' was removed by theirs and changed by ours", o.Name), o, nil)
+			}
+
+			continue
+		}
+
+		done[t] = true
+
+		if message := renamedAndChanged(b, o, t); message != "" {
+			merged = append(merged, o)
+			m.addGoal(o, message, o, t)
+			continue
+		}
+
+		merged = append(merged, m.mergeGoal(b, o, t))
+	}
+
+	for _, t := range theirs {
+
+		if done[t] {
+			continue
+		}
+
+		b, inBase := theirsBase[t]
+
+		if !inBase {
+			merged = append(merged, t)
+			continue
+		}
+
+		if _, inOurs := baseOurs[b]; !inOurs && goalChanged(b, t) {
+			merged = append(merged, t)
+			m.addGoal(t, fmt.Sprintf("Goal '%s' was removed by ours and changed by theirs", t.Name), nil, t)
+		}
+	}
+
+	return merged
+}
+
+// mergeGoal merges a goal both sides kept, or both added
+func (m *MergeResult) mergeGoal(base, ours, theirs *Goal) *Goal {
+
+	merged := &Goal{
+		Node:         ours.Node,
+		NameLocation: ours.NameLocation,
+		Name:         ours.Name,
+		Tags:         mergeTags(base.Tags, ours.Tags, theirs.Tags),
+		DocString:    ours.DocString,
+		DataTable:    ours.DataTable,
+	}
+
+	conflicts := make([]string, 0)
+
+	if fromTheirs, ok := choose(base.Name, ours.Name, theirs.Name); !ok {
+		conflicts = append(conflicts, "name")
+	} else if fromTheirs {
+		merged.Name, merged.NameLocation = theirs.Name, theirs.NameLocation
+	}
+
+	if fromTheirs, ok := choose(docStringText(base.DocString), docStringText(ours.DocString), docStringText(theirs.DocString)); !ok {
+		conflicts = append(conflicts, conflictDocString)
+	} else if fromTheirs {
+		merged.DocString = theirs.DocString
+	}
+
+	if fromTheirs, ok := choose(tableText(base.DataTable), tableText(ours.DataTable), tableText(theirs.DataTable)); !ok {
+		conflicts = append(conflicts, conflictDataTable)
+	} else if fromTheirs {
+		merged.DataTable = theirs.DataTable
+	}
+
+	if len(conflicts) > 0 {
+		m.addGoal(merged, fmt.Sprintf("Both sides changed the %s of goal '%s'", strings.Join(conflicts, " and "), merged.Name), ours, theirs)
+	}
+
+	return merged
+}
+
+// renamedAndChanged describes a goal that one side renamed and the other
+// changed, or returns "". A goal matched by being similar, rather than by
+// name, may have been removed and another added instead, so the other side's
+// change can't be made to it.
+func renamedAndChanged(base, ours, theirs *Goal) string {
+
+	renamed := func(g *Goal) bool {
+		return normaliseName(g.Name) != normaliseName(base.Name)
+	}
+
+	switch {
+	case !goalChanged(ours, theirs):
+		return ""
+
+	case renamed(ours) && goalChanged(base, theirs):
+		return fmt.Sprintf("Goal '%s' was renamed to '%s' by ours, or replaced, and changed by theirs", base.Name, ours.Name)
+
+	case renamed(theirs) && goalChanged(base, ours):
+		return fmt.Sprintf("Goal '%s' was renamed to '%s' by theirs, or replaced, and changed by ours", base.Name, theirs.Name)
+	}
+
+	return ""
+}
+
+func goalChanged(a, b *Goal) bool {
+
+	changed := false
+
+	diffGoal(a, b, func(*Change) {
+		changed = true
+	})
+
+	return changed
+}
+
+// choose picks the side of a three-way merge to keep: the side that changed
+// a part since the base, or either if both made the same change. It's a
+// conflict if both changed it differently.
+func choose(base, ours, theirs string) (fromTheirs bool, ok bool) {
+
+	switch {
+	case ours == theirs, theirs == base:
+		return false, true
+
+	case ours == base:
+		return true, true
+	}
+
+	return false, false
+}
+
+// mergeNames keeps the names both sides kept, and those either added, in our
+// order then theirs
+func mergeNames(base, ours, theirs []string) []string {
+
+	merged := make([]string, 0, len(ours))
+
+	removedByTheirs, addedByTheirs := difference(base, theirs)
+
+	in := func(list []string, s string) bool {
+		for _, l := range list {
+			if l == s {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	for _, name := range ours {
+		if !in(removedByTheirs, name) {
+			merged = append(merged, name)
+		}
+	}
+
+	for _, name := range addedByTheirs {
+		if !in(ours, name) {
+			merged = append(merged, name)
+		}
+	}
+
+	return merged
+}
+
+func mergeTags(base, ours, theirs []*gherkin.Tag) []*gherkin.Tag {
+
+	tags := make(map[string]*gherkin.Tag)
+
+	for _, tag := range append(append([]*gherkin.Tag{}, theirs...), ours...) {
+		tags[tag.Name] = tag
+	}
+
+	merged := make([]*gherkin.Tag, 0)
+
+	for _, name := range mergeNames(tagNames(base), tagNames(ours), tagNames(theirs)) {
+		merged = append(merged, tags[name])
+	}
+
+	return merged
+}
+
+func mergeAliases(base, ours, theirs []*Alias) []*Alias {
+
+	aliases := make(map[string]*Alias)
+
+	for _, alias := range append(append([]*Alias{}, theirs...), ours...) {
+		aliases[alias.Name] = alias
+	}
+
+	var merged []*Alias
+
+	for _, name := range mergeNames(aliasNames(base), aliasNames(ours), aliasNames(theirs)) {
+		merged = append(merged, aliases[name])
+	}
+
+	return merged
+}
+
+func actorSide(a *Actor) func(w *writer) error {
+
+	if a == nil {
+		return nil
+	}
+
+	return func(w *writer) error {
+
+		side := newWriter(w.writer)
+
+		if err := side.setLanguage(a.Language); err != nil {
+			return err
+		}
+
+		if err := side.writeComments(a.Comments); err != nil {
+			return err
+		}
+
+		return side.writeActor(a, nil)
+	}
+}
+
+// commentText is the comments as they're written, so that a merge can keep
+// the side that changed them
+func commentText(comments []*Comment) string {
+
+	lines := make([]string, 0, len(comments))
+
+	for _, comment := range comments {
+		lines = append(lines, comment.Text)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func commentsSide(comments []*Comment) func(w *writer) error {
+
+	if len(comments) == 0 {
+		return nil
+	}
+
+	return func(w *writer) error {
+		return w.writeComments(comments)
+	}
+}
+
+func goalSide(g *Goal) func(w *writer) error {
+
+	if g == nil {
+		return nil
+	}
+
+	return func(w *writer) error {
+		return w.writeGoal(g)
+	}
+}
+
+func docStringSide(d *DocString) func(w *writer) error {
+
+	if d == nil {
+		return nil
+	}
+
+	return func(w *writer) error {
+		return w.writeDocString(d)
+	}
+}
+
+func tableSide(t *DataTable) func(w *writer) error {
+
+	if t == nil {
+		return nil
+	}
+
+	return func(w *writer) error {
+		return w.writeTable(t)
+	}
+}
+
+func (m *MergeResult) conflict(part string) *Conflict {
+
+	if m == nil {
+		return nil
+	}
+
+	return m.parts[part]
+}
+
+func (m *MergeResult) goalConflict(goal *Goal) *Conflict {
+
+	if m == nil {
+		return nil
+	}
+
+	return m.goals[goal]
+}
+
+// Clean reports whether the merge had no conflicts
+func (m *MergeResult) Clean() bool {
+	return len(m.Conflicts) == 0
+}
+
+// Write writes the merged actor, with each conflict written in place of the
+// part it's about, so that it can be resolved by hand. It writes nothing if
+// both sides removed the actor.
+func (m *MergeResult) Write(w io.Writer) error {
+
+	writer := newWriter(w)
+
+	if m.Actor == nil {
+		return nil
+	}
+
+	if m.whole != nil {
+		return writer.writeConflict(m.whole)
+	}
+
+	if err := writer.setLanguage(m.Actor.Language); err != nil {
+		return err
+	}
+
+	return writer.writeActor(m.Actor, m)
+}
+
+func (m *MergeResult) WriteToFile(name string) error {
+
+	buf := &bytes.Buffer{}
+
+	if err := m.Write(buf); err != nil {
+		return err
+	}
+
+	_, err := writeFile(name, buf.Bytes(), 0644, "", nil, true)
+
+	return err
+}
@@ -0,0 +1,302 @@
+package actor
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeMerge(t *testing.T, m *MergeResult) string {
+
+	buf := &bytes.Buffer{}
+	assert.Nil(t, m.Write(buf))
+
+	return buf.String()
+}
+
+func Test_ItMergesIndependentChanges(t *testing.T) {
+
+	base := parseForDiff(t, `@staff
+Actor: Support agent
+    Handles refunds
+
+    @mobile
+    Goal: Refund an order
+
+    Goals:
+        Answer tickets
+        Close tickets
+        Escalate complaints
+`)
+
+	ours := parseForDiff(t, `@staff @web
+Actor: Support agent
+    Handles refunds
+
+    @mobile @web
+    Goal: Refund an order
+
+    Goals:
+        Answer tickets
+        Escalate complaints
+`)
+
+	theirs := parseForDiff(t, `# language: fr
+@staff
+Acteur: Support agent
+    Alias:
+        Helpdesk
+    Handles refunds and returns
+
+    @mobile
+    Objectif: Refund an order
+
+    Objectifs:
+        Escalate complaints to a manager
+        Close tickets
+        Answer tickets
+        Chat with customers
+`)
+
+	m := Merge(base, ours, theirs)
+
+	assert.True(t, m.Clean())
+	assert.Equal(t, `# language: fr
+@staff @web
+Acteur: Support agent
+    Alias:
+        Helpdesk
+    Handles refunds and returns
+
+    @mobile @web
+    Objectif: Refund an order
+
+    Objectifs:
+        Answer tickets
+        Escalate complaints to a manager
+        Chat with customers
+`, writeMerge(t, m))
+
+	// The same change made on both sides is made once
+	m = Merge(base, ours, ours)
+	assert.True(t, m.Clean())
+	assert.Equal(t, Changes{}, Diff(ours, m.Actor))
+}
+
+func Test_ItWritesConflictsBetweenMarkers(t *testing.T) {
+
+	base := parseForDiff(t, `Actor: Shopper
+    Buys things
+
+    Goal: Pay
+        """
+        By card
+        """
+
+    Goals:
+        Browse
+        Return
+`)
+
+	ours := parseForDiff(t, `Actor: Online shopper
+    Buys things online
+
+    Goal: Pay
+        """
+        By card or cash
+        """
+
+    @web
+    Goal: Return
+
+    Goals:
+        Browse
+`)
+
+	theirs := parseForDiff(t, `Actor: Customer
+    Buys things in store
+
+    @mobile
+    Goal: Pay
+        """
+        By phone
+        """
+
+    Goals:
+        Browse
+        Track orders
+`)
+
+	m := Merge(base, ours, theirs)
+
+	assert.False(t, m.Clean())
+	assert.Equal(t, []*Conflict{
+		{Part: "name", Message: "Both sides renamed the actor"},
+		{Part: "blurb", Message: "Both sides changed the blurb"},
+		{Part: "goal", Goal: "Pay", Message: "Both sides changed the doc string of goal 'Pay'"},
+		{Part: "goal", Goal: "Return", Message: "Goal 'Return' was removed by theirs and changed by ours"},
+	}, withoutSides(m.Conflicts))
+
+	assert.Equal(t, `<<<<<<< ours
+Actor: Online shopper
+=======
+Actor: Customer
+>>>>>>> theirs
+<<<<<<< ours
+    Buys things online
+=======
+    Buys things in store
+>>>>>>> theirs
+
+<<<<<<< ours
+    Goal: Pay
+        """
+        By card or cash
+        """
+=======
+    @mobile
+    Goal: Pay
+        """
+        By phone
+        """
+>>>>>>> theirs
+<<<<<<< ours
+    @web
+    Goal: Return
+=======
+>>>>>>> theirs
+
+    Goals:
+        Browse
+        Track orders
+`, writeMerge(t, m))
+
+	// Where there's a conflict the actor has our side, with the tags of both
+	assert.Equal(t, "Online shopper", m.Actor.Name)
+	assert.Equal(t, "By card or cash", m.Actor.Goals[0].DocString.Content)
+	assert.Equal(t, "@mobile", tagList(m.Actor.Goals[0].Tags))
+}
+
+func Test_ItDoesntMergeChangesIntoARenamedGoal(t *testing.T) {
+
+	base := parseForDiff(t, "Actor: Member\n    Goals:\n        Log in\n        Pay the bill\n")
+
+	// Log out isn't a rename of Log in, so their tag isn't moved to it, and
+	// Log in was removed by one side and changed by the other
+	ours := parseForDiff(t, "Actor: Member\n    Goals:\n        Log out\n        Pay the bill\n")
+	theirs := parseForDiff(t, "Actor: Member\n    @auth\n    Goal: Log in\n    Goals:\n        Pay the bill\n")
+
+	m := Merge(base, ours, theirs)
+	assert.Equal(t, []*Conflict{
+		{Part: "goal", Goal: "Log in", Message: "Goal 'Log in' was removed by ours and changed by theirs"},
+	}, withoutSides(m.Conflicts))
+	assert.Equal(t, "Actor: Member\n<<<<<<< ours\n=======\n    @auth\n    Goal: Log in\n>>>>>>> theirs\n\n    Goals:\n        Log out\n        Pay the bill\n", writeMerge(t, m))
+
+	// Pay the bills probably is a rename of Pay the bill, but may not be
+	ours = parseForDiff(t, "Actor: Member\n    Goals:\n        Log in\n        Pay the bills\n")
+	theirs = parseForDiff(t, "Actor: Member\n    @card\n    Goal: Pay the bill\n    Goals:\n        Log in\n")
+
+	m = Merge(base, ours, theirs)
+	assert.Equal(t, []*Conflict{
+		{Part: "goal", Goal: "Pay the bills", Message: "Goal 'Pay the bill' was renamed to 'Pay the bills' by ours, or replaced, and changed by theirs"},
+	}, withoutSides(m.Conflicts))
+	assert.Equal(t, `Actor: Member
+<<<<<<< ours
+    Goal: Pay the bills
+=======
+    @card
+    Goal: Pay the bill
+>>>>>>> theirs
+
+    Goals:
+        Log in
+`, writeMerge(t, m))
+
+	// Renamed by one side and left alone by the other is a rename
+	m = Merge(base, ours, base)
+	assert.True(t, m.Clean())
+	assert.Equal(t, Changes{}, Diff(ours, m.Actor))
+}
+
+func Test_ItMergesComments(t *testing.T) {
+
+	base := parseForDiff(t, "# owner: team-a\nActor: Shopper\n    Goals:\n        One\n")
+	ours := parseForDiff(t, "# owner: team-a\nActor: Shopper\n    Goals:\n        One\n        Two\n")
+	theirs := parseForDiff(t, "# owner: team-a\nActor: Shopper\n    Goals:\n        One\n        Three\n")
+
+	// Comments neither side changed are kept, at the top
+	m := Merge(base, ours, theirs)
+	assert.True(t, m.Clean())
+	assert.Equal(t, "# owner: team-a\nActor: Shopper\n    Goals:\n        One\n        Two\n        Three\n", writeMerge(t, m))
+
+	// The side that changed them wins
+	theirs = parseForDiff(t, "# owner: team-b\nActor: Shopper\n    Goals:\n        One # the first\n")
+
+	m = Merge(base, ours, theirs)
+	assert.True(t, m.Clean())
+	assert.Equal(t, "# owner: team-b\n# the first\nActor: Shopper\n    Goals:\n        One\n        Two\n", writeMerge(t, m))
+
+	// Comments both sides removed are gone
+	removed := parseForDiff(t, "Actor: Shopper\n    Goals:\n        One\n")
+
+	m = Merge(base, removed, removed)
+	assert.True(t, m.Clean())
+	assert.Equal(t, "Actor: Shopper\n    Goals:\n        One\n", writeMerge(t, m))
+
+	// Both sides changed them differently
+	ours = parseForDiff(t, "# owner: team-c\nActor: Shopper\n    Goals:\n        One\n")
+
+	m = Merge(base, ours, theirs)
+	assert.Equal(t, []*Conflict{
+		{Part: "comments", Message: "Both sides changed the comments"},
+	}, withoutSides(m.Conflicts))
+	assert.Equal(t, `<<<<<<< ours
+# owner: team-c
+=======
+# owner: team-b
+# the first
+>>>>>>> theirs
+Actor: Shopper
+    Goals:
+        One
+`, writeMerge(t, m))
+}
+
+func withoutSides(conflicts []*Conflict) []*Conflict {
+
+	stripped := make([]*Conflict, 0, len(conflicts))
+
+	for _, c := range conflicts {
+		stripped = append(stripped, &Conflict{Part: c.Part, Goal: c.Goal, Message: c.Message})
+	}
+
+	return stripped
+}
+
+func Test_ItMergesAddedAndRemovedActors(t *testing.T) {
+
+	base := parseForDiff(t, "Actor: Visitor\n    Goals:\n        Read\n")
+	changed := parseForDiff(t, "Actor: Visitor\n    Goals:\n        Read\n        Comment\n")
+
+	// Removed by one side and left alone by the other
+	m := Merge(base, nil, base)
+	assert.True(t, m.Clean())
+	assert.Nil(t, m.Actor)
+	assert.Equal(t, "", writeMerge(t, m))
+
+	// Removed by one side and changed by the other
+	m = Merge(base, changed, nil)
+	assert.Equal(t, []*Conflict{{Part: "actor", Message: "The actor was removed by theirs and changed by ours"}}, withoutSides(m.Conflicts))
+	assert.Equal(t, changed, m.Actor)
+	assert.Equal(t, "<<<<<<< ours\n\nActor: Visitor\n    Goals:\n        Read\n        Comment\n=======\n>>>>>>> theirs\n", writeMerge(t, m))
+
+	// Added by both sides
+	m = Merge(nil, base, changed)
+	assert.True(t, m.Clean())
+	assert.Equal(t, Changes{}, Diff(changed, m.Actor))
+
+	m = Merge(nil, nil, changed)
+	assert.True(t, m.Clean())
+	assert.Equal(t, changed, m.Actor)
+}
@@ -0,0 +1,141 @@
+// Package narrative checks that the narrative of each .feature file names one
+// of the project's actors:
+//
+//	Feature: Moderation
+//	    As a site administrator
+//	    I want to remove spam
+//	    So that visitors trust the comments
+//
+// Names are matched ignoring case and differences in whitespace. A feature
+// without an "As a ..." line isn't checked.
+package narrative
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strings"
+
+	gherkin "github.com/cucumber/gherkin-go"
+	"github.com/dryvercorp/actor"
+)
+
+// roleMatcher finds the role named in an "As a ..." line, which ends at the
+// first comma or "I want", for a narrative written on one line
+var roleMatcher = regexp.MustCompile(`(?i)^\s*as an?\s+(.+?)\s*(?:,|\bi want\b|$)`)
+
+// Role returns the role named by a line of a narrative, such as
+// "As a site administrator," or "As a user I want to log in"
+func Role(line string) (string, bool) {
+
+	matches := roleMatcher.FindStringSubmatch(line)
+
+	if matches == nil {
+		return "", false
+	}
+
+	return matches[1], true
+}
+
+// Mismatch is a feature whose narrative names a role that isn't an actor
+type Mismatch struct {
+	Path     string
+	Location *gherkin.Location // Of the "As a ..." line
+	Role     string
+	Closest  *actor.ProjectFile // The actor with the most similar name, if any is close
+}
+
+// Message describes the mismatch, with where the closest actor is defined
+func (m *Mismatch) Message() string {
+
+	if m.Closest == nil {
+		return fmt.Sprintf("'%s' is not an actor", m.Role)
+	}
+
+	location := m.Closest.Actor.Location
+
+	return fmt.Sprintf("'%s' is not an actor, did you mean '%s' (%s:%d:%d)?", m.Role, m.Closest.Actor.Name, m.Closest.Path, location.Line, location.Column)
+}
+
+func (m *Mismatch) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s", m.Path, m.Location.Line, m.Location.Column, m.Message())
+}
+
+// CheckFeature parses a feature and returns the mismatch if its narrative
+// doesn't name an actor of the project, or nil
+func CheckFeature(project *actor.Project, path string, r io.Reader) (*Mismatch, error) {
+
+	content, err := ioutil.ReadAll(r)
+
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := gherkin.ParseGherkinDocument(bytes.NewReader(content))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if doc.Feature == nil {
+		return nil, nil
+	}
+
+	role, location := findRole(doc.Feature, content)
+
+	if location == nil || project.FindActor(role) != nil {
+		return nil, nil
+	}
+
+	return &Mismatch{Path: path, Location: location, Role: role, Closest: project.SuggestActor(role)}, nil
+}
+
+// CheckFeatureFile is CheckFeature for a file on disk
+func CheckFeatureFile(project *actor.Project, path string) (*Mismatch, error) {
+
+	content, err := ioutil.ReadFile(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return CheckFeature(project, path, bytes.NewReader(content))
+}
+
+// findRole returns the role named by the feature's description, and where
+// the line naming it is. The description doesn't keep its lines' locations,
+// so the line is found again in the file after the Feature: line.
+func findRole(feature *gherkin.Feature, content []byte) (string, *gherkin.Location) {
+
+	for _, description := range strings.Split(feature.Description, "\n") {
+
+		role, ok := Role(description)
+
+		if !ok {
+			continue
+		}
+
+		description = strings.TrimSpace(description)
+
+		scanner := bufio.NewScanner(bytes.NewReader(content))
+		line_number := 0
+
+		for scanner.Scan() {
+			line_number++
+
+			raw_line := scanner.Text()
+
+			if line_number > feature.Location.Line && strings.TrimSpace(raw_line) == description {
+				column := len(raw_line) - len(strings.TrimLeft(raw_line, " \t")) + 1
+				return role, &gherkin.Location{Line: line_number, Column: column}
+			}
+		}
+
+		return role, &gherkin.Location{Line: feature.Location.Line, Column: feature.Location.Column}
+	}
+
+	return "", nil
+}
@@ -0,0 +1,131 @@
+package narrative
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gherkin "github.com/cucumber/gherkin-go"
+	"github.com/dryvercorp/actor"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestProject(t *testing.T) *actor.Project {
+
+	parse := func(path, content string) *actor.ProjectFile {
+		a, err := actor.NewParser(strings.NewReader(content)).Parse()
+		assert.Nil(t, err)
+		return &actor.ProjectFile{Path: path, Actor: a}
+	}
+
+	return &actor.Project{Files: []*actor.ProjectFile{
+		parse("actors/admin.actor", "@staff\nActor: Site  Administrator\n    Aliases:\n        Admin\n"),
+		parse("actors/visitor.actor", "Actor: Visitor\n"),
+	}}
+}
+
+func Test_ItFindsTheRole(t *testing.T) {
+
+	tests := []struct {
+		line string
+		role string
+		ok   bool
+	}{
+		{"As a visitor", "visitor", true},
+		{"  as an Administrator,", "Administrator", true},
+		{"\tAS A site  administrator ,", "site  administrator", true},
+		{"As a site administrator, I want to remove spam, so that visitors trust the comments", "site administrator", true},
+		{"As a user I want to log in", "user", true},
+		{"As an editor I WANT to publish", "editor", true},
+		{"As I want", "", false},
+		{"I want to read", "", false},
+		{"Asa visitor", "", false},
+	}
+
+	for _, test := range tests {
+		role, ok := Role(test.line)
+		assert.Equal(t, test.ok, ok, test.line)
+		assert.Equal(t, test.role, role, test.line)
+	}
+}
+
+func Test_ItChecksTheNarrative(t *testing.T) {
+
+	project := newTestProject(t)
+
+	tests := []struct {
+		feature  string
+		mismatch *Mismatch
+	}{
+		{
+			feature: "Feature: Reading\n  As a visitor\n  I want to read\n",
+		},
+		{
+			feature: "Feature: Moderation\n\n    As a SITE administrator,\n    I want to remove spam\n",
+		},
+		{
+			feature: "Feature: Moderation\n  As an ADMIN\n",
+		},
+		{
+			feature: "Feature: Moderation\n  As a site administrator, I want to remove spam, so that visitors trust the comments\n",
+		},
+		{
+			feature: "Feature: Reading\n  As a visitor I want to read\n",
+		},
+		{
+			feature: "Feature: No narrative\n  Just a description\n\n  Scenario: Nothing\n",
+		},
+		{
+			feature: "Feature: Moderation\n\n    In order to keep things clean\n    As a site admnistrator\n    I want to remove spam\n",
+			mismatch: &Mismatch{
+				Path:     "features/moderation.feature",
+				Location: &gherkin.Location{Line: 4, Column: 5},
+				Role:     "site admnistrator",
+				Closest:  project.Files[0],
+			},
+		},
+		{
+			feature: "Feature: Hacking\n\tAs an intruder\n",
+			mismatch: &Mismatch{
+				Path:     "features/moderation.feature",
+				Location: &gherkin.Location{Line: 2, Column: 2},
+				Role:     "intruder",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		mismatch, err := CheckFeature(project, "features/moderation.feature", strings.NewReader(test.feature))
+		assert.Nil(t, err, test.feature)
+		assert.Equal(t, test.mismatch, mismatch, test.feature)
+	}
+}
+
+func Test_ItReportsBothLocations(t *testing.T) {
+
+	project := newTestProject(t)
+
+	mismatch, err := CheckFeature(project, "features/moderation.feature", strings.NewReader("Feature: Moderation\n  As a site admnistrator\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, "features/moderation.feature:2:3: 'site admnistrator' is not an actor, did you mean 'Site  Administrator' (actors/admin.actor:2:1)?", mismatch.String())
+
+	mismatch, err = CheckFeature(project, "features/hacking.feature", strings.NewReader("Feature: Hacking\n  As an intruder\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, "features/hacking.feature:2:3: 'intruder' is not an actor", mismatch.String())
+}
+
+func Test_ItChecksAFeatureFile(t *testing.T) {
+
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "hacking.feature")
+	assert.Nil(t, ioutil.WriteFile(path, []byte("Feature: Hacking\n  As an intruder\n"), 0644))
+
+	mismatch, err := CheckFeatureFile(newTestProject(t), path)
+	assert.Nil(t, err)
+	assert.Equal(t, path, mismatch.Path)
+
+	_, err = CheckFeatureFile(newTestProject(t), filepath.Join(dir, "missing.feature"))
+	assert.NotNil(t, err)
+}
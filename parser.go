@@ -1,66 +1,135 @@
 package actor
 
 import (
-	"bytes"
 	"fmt"
 	"io"
-	"os"
+	"strings"
 
 	gherkin "github.com/cucumber/gherkin-go"
 )
 
 type Parser interface {
 	Parse() (*Actor, error)
+
+	// Diagnostics returns the errors and warnings found by the last Parse
+	Diagnostics() []*Diagnostic
+}
+
+type Mode int
+
+const (
+	// ModeDefault rejects anything it doesn't understand
+	ModeDefault Mode = iota
+
+	// ModeLenient treats unknown keywords as blurb text, with a warning
+	ModeLenient
+
+	// ModeStrict turns indentation warnings into errors, and also rejects
+	// indentation that doesn't step by the same amount at every level
+	ModeStrict
+)
+
+type ParserOptions struct {
+	Mode Mode
+
+	// TabWidth is the distance between tab stops when working out how far a
+	// line is indented, and defaults to 4
+	TabWidth int
+
+	// Tags, if set, is the registry that every tag must be in
+	Tags *TagRegistry
 }
 
 type parser struct {
 	reader      io.Reader
+	options     ParserOptions
 	actor       *Actor
+	goal        *Goal
+	table       *DataTable
 	pendingTags []*gherkin.Tag
+	comments    []*Comment
+	diagnostics []*Diagnostic
+
+	// The lexer of the last Parse, and any earlier lex it may reuse
+	lexer   *lexer
+	history *lexerHistory
+
+	// The file being parsed, if any
+	source *fileSource
+
+	// Whether lines are fed one at a time by a Stream, rather than as a tree
+	streaming bool
 }
 
 func NewParser(r io.Reader) Parser {
+	return NewParserWithOptions(r, ParserOptions{})
+}
+
+func NewParserWithOptions(r io.Reader, options ParserOptions) Parser {
 	return &parser{
 		reader:      r,
+		options:     options,
 		pendingTags: make([]*gherkin.Tag, 0),
+		diagnostics: make([]*Diagnostic, 0),
 	}
 }
 
 func NewFileParser(path string) (Parser, error) {
+	return NewFileParserWithOptions(path, ParserOptions{})
+}
+
+func NewFileParserWithOptions(path string, options ParserOptions) (Parser, error) {
 
-	file, err := os.Open(path)
-	defer file.Close()
+	buf, source, err := readFile(path)
 
 	if err != nil {
 		return nil, err
 	}
 
-	buf := bytes.NewBuffer(nil)
+	p := NewParserWithOptions(buf, options).(*parser)
+	p.source = source
 
-	if _, err := io.Copy(buf, file); err != nil {
-		return nil, err
-	}
+	return p, nil
+}
 
-	return NewParser(buf), nil
+func (p *parser) Diagnostics() []*Diagnostic {
+	return p.diagnostics
 }
 
 func (p *parser) Parse() (actor *Actor, err error) {
 
 	p.resetTags()
+	p.comments = make([]*Comment, 0)
+	p.diagnostics = make([]*Diagnostic, 0)
 
 	lex := newLexer(p.reader)
+	lex.strict = p.options.Mode == ModeStrict
+	lex.history = p.history
+	p.lexer = lex
+
+	if p.options.TabWidth > 0 {
+		lex.tabWidth = p.options.TabWidth
+	}
+
 	tkn := newTokeniser()
+	tkn.lenient = p.options.Mode == ModeLenient
 
 	tree, lex_err := lex.lex()
+	p.diagnostics = append(p.diagnostics, lex.diagnostics...)
 
 	if lex_err != nil {
-		return nil, fmt.Errorf("Lexer error: %s", err)
+		return nil, fmt.Errorf("Lexer error: %s", lex_err)
 	}
 
 	if err := p.parseTree(tree, tkn); err != nil {
 		return nil, err
 	}
 
+	if p.actor != nil {
+		p.actor.Comments = p.comments
+		p.actor.source = p.source
+	}
+
 	return p.actor, nil
 }
 
@@ -68,18 +137,95 @@ func (p *parser) resetTags() {
 	p.pendingTags = make([]*gherkin.Tag, 0)
 }
 
-func (p *parser) addTag(l *line, name string) {
+func (p *parser) addTag(l *line, t token) error {
+
+	tag := &gherkin.Tag{
+		Location: l.location(t.column),
+		Name:     t.content,
+	}
+
+	if err := p.checkTag(tag); err != nil {
+		return err
+	}
+
+	p.pendingTags = append(p.pendingTags, tag)
+
+	return nil
+}
+
+// checkTag makes sure a tag is registered, if there is a registry
+func (p *parser) checkTag(tag *gherkin.Tag) error {
+
+	registry := p.options.Tags
+
+	if registry == nil {
+		return nil
+	}
+
+	definition := registry.Lookup(tag.Name)
+
+	if definition == nil {
+
+		if suggestion := registry.Suggest(tag.Name); suggestion != "" {
+			return p.report(tag.Location, "Unknown tag '@%s', did you mean '@%s'?", tag.Name, suggestion)
+		}
+
+		return p.report(tag.Location, "Unknown tag '@%s'", tag.Name)
+	}
+
+	if definition.Deprecated && definition.ReplacedBy != "" {
+		p.warnAt(tag.Location, "Tag '@%s' is deprecated, use '@%s' instead", tag.Name, definition.ReplacedBy)
+	} else if definition.Deprecated {
+		p.warnAt(tag.Location, "Tag '@%s' is deprecated", tag.Name)
+	}
+
+	return nil
+}
+
+// checkTagScope makes sure the pending tags may be used on an actor or goal
+func (p *parser) checkTagScope(scope TagScope) error {
+
+	if p.options.Tags == nil {
+		return nil
+	}
 
-	p.pendingTags = append(
-		p.pendingTags,
-		&gherkin.Tag{
-			Location: &gherkin.Location{
-				Line:   l.line,
-				Column: l.column,
-			},
-			Name: name,
-		},
-	)
+	for _, tag := range p.pendingTags {
+		if definition := p.options.Tags.Lookup(tag.Name); definition != nil && !definition.Allows(scope) {
+			if err := p.report(tag.Location, "Tag '@%s' can't be used on %s %s", tag.Name, article(scope), scope); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func article(scope TagScope) string {
+
+	if scope == TagScopeActor {
+		return "an"
+	}
+
+	return "a"
+}
+
+func (p *parser) addComment(l *line) {
+
+	content := string(l.content)
+
+	// Table rows and doc strings have no comments, and the language header
+	// isn't one
+	if strings.HasPrefix(content, "|") || l.content.docStringDelimiter() != "" || (p.actor == nil && languageMatcher.MatchString(content)) {
+		return
+	}
+
+	if i := indexUnescaped(content, '#'); i >= 0 {
+
+		comment := &Comment{Text: content[i:]}
+		comment.Location = l.location(characters(l.content, i))
+
+		p.comments = append(p.comments, comment)
+	}
 }
 
 func (p *parser) addPendingTagsToList(list *[]*gherkin.Tag) {
@@ -88,12 +234,42 @@ func (p *parser) addPendingTagsToList(list *[]*gherkin.Tag) {
 }
 
 func (p *parser) err(branch *line, e string, args ...interface{}) error {
-	return fmt.Errorf("[Line %04d:%02d] %s", branch.line, branch.column, fmt.Sprintf(e, args...))
+	return p.errAt(branch.location(0), e, args...)
+}
+
+func (p *parser) errAt(location *gherkin.Location, e string, args ...interface{}) error {
+
+	message := fmt.Sprintf(e, args...)
+
+	p.diagnostics = append(p.diagnostics, newDiagnostic(SeverityError, location.Line, location.Column, message))
+
+	return fmt.Errorf("[Line %04d:%02d] %s", location.Line, location.Column, message)
+}
+
+func (p *parser) warn(branch *line, e string, args ...interface{}) {
+	p.warnAt(branch.location(0), e, args...)
+}
+
+func (p *parser) warnAt(location *gherkin.Location, e string, args ...interface{}) {
+	p.diagnostics = append(p.diagnostics, newDiagnostic(SeverityWarning, location.Line, location.Column, fmt.Sprintf(e, args...)))
+}
+
+// report is only a warning in lenient mode, and otherwise an error
+func (p *parser) report(location *gherkin.Location, e string, args ...interface{}) error {
+
+	if p.options.Mode == ModeLenient {
+		p.warnAt(location, e, args...)
+		return nil
+	}
+
+	return p.errAt(location, e, args...)
 }
 
 func (p *parser) parseTree(tree lexerTree, tkn *tokeniser) error {
 	for _, branch := range tree {
 
+		p.addComment(branch)
+
 		tokens, err := tkn.tokenise(branch)
 
 		if err != nil {
@@ -102,10 +278,22 @@ func (p *parser) parseTree(tree lexerTree, tkn *tokeniser) error {
 
 		for _, token := range tokens {
 
+			// Rows only continue a table while they are adjacent
+			if token.kind != token_tableRow && token.kind != token_tableCell {
+				p.table = nil
+			}
+
 			switch token.kind {
 
+			case token_language:
+				if err := p.parseLanguage(branch, token, tkn); err != nil {
+					return err
+				}
+
 			case token_tag:
-				p.addTag(branch, token.content)
+				if err := p.addTag(branch, token); err != nil {
+					return err
+				}
 
 			case token_actorDefinition:
 				if err := p.parseActorDefinition(branch, token, tkn); err != nil {
@@ -122,15 +310,61 @@ func (p *parser) parseTree(tree lexerTree, tkn *tokeniser) error {
 					return err
 				}
 
+			case token_aliases:
+				if err := p.parseAliases(branch, token, tkn); err != nil {
+					return err
+				}
+
 			case token_text:
 				if err := p.parseText(branch, token, tkn); err != nil {
 					return err
 				}
 
+			case token_unknownKeyword:
+				p.warn(branch, "Unrecognised keyword '%s' treated as text", token.keyword)
+
+				if err := p.parseText(branch, token, tkn); err != nil {
+					return err
+				}
+
+			case token_docString:
+				if err := p.parseDocString(branch, token); err != nil {
+					return err
+				}
+
+			case token_tableRow:
+				if err := p.parseTableRow(branch); err != nil {
+					return err
+				}
+
+			case token_tableCell:
+				p.parseTableCell(branch, token)
+
 			default:
 				return p.err(branch, "Parse error: %s", branch.content)
 			}
 		}
+
+		// All the cells of a row are known once its tokens are used up
+		if len(tokens) > 0 && tokens[0].kind == token_tableRow {
+			if err := p.checkTableRow(branch); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (p *parser) parseLanguage(branch *line, t token, tkn *tokeniser) error {
+
+	// Once the actor has started the header is just another comment
+	if p.actor != nil {
+		return nil
+	}
+
+	if err := tkn.setLanguage(t.content); err != nil {
+		return p.err(branch, err.Error())
 	}
 
 	return nil
@@ -148,10 +382,13 @@ func (p *parser) parseActorDefinition(branch *line, t token, tkn *tokeniser) err
 
 	p.actor = NewActor()
 	p.actor.Name = t.content
+	p.actor.Language = tkn.dialect.language
+
+	p.actor.Location = branch.location(0)
+	p.actor.NameLocation = branch.location(t.column)
 
-	p.actor.Location = &gherkin.Location{
-		Line:   branch.line,
-		Column: branch.column,
+	if err := p.checkTagScope(TagScopeActor); err != nil {
+		return err
 	}
 
 	p.addPendingTagsToList(&p.actor.Tags)
@@ -170,55 +407,172 @@ func (p *parser) parseGoal(branch *line, t token, tkn *tokeniser) error {
 	}
 
 	goal := &Goal{Name: t.content}
-	goal.Location = &gherkin.Location{
-		Line:   branch.line,
-		Column: branch.column,
+	goal.Location = branch.location(0)
+	goal.NameLocation = branch.location(t.column)
+
+	if err := p.checkTagScope(TagScopeGoal); err != nil {
+		return err
 	}
 
 	p.addPendingTagsToList(&goal.Tags)
 
 	p.actor.Goals = append(p.actor.Goals, goal)
 
+	p.goal, p.table = goal, nil
+
+	// A stream closes the goal once its children have been read
+	if p.streaming {
+		return nil
+	}
+
+	defer p.endGoal()
+
 	return p.parseTree(branch.children, tkn)
 }
 
+func (p *parser) endGoal() {
+	p.goal, p.table = nil, nil
+}
+
 func (p *parser) parseGoals(branch *line, t token, tkn *tokeniser) error {
 
 	if p.actor == nil {
 		return p.err(branch, "Goals keyword outside of actor context")
 	}
 
+	if err := p.checkTagScope(TagScopeGoal); err != nil {
+		return err
+	}
+
+	// The tags apply to every goal in the list, which a stream reads later
+	if p.streaming {
+		return nil
+	}
+
 	for _, goalDef := range branch.children {
+		if err := p.parseGoalListItem(goalDef, tkn); err != nil {
+			return err
+		}
+	}
 
-		tokens, err := tkn.tokenise(goalDef)
+	p.resetTags()
 
-		if err != nil {
-			return p.err(goalDef, err.Error())
+	return nil
+}
+
+func (p *parser) parseGoalListItem(goalDef *line, tkn *tokeniser) error {
+
+	p.addComment(goalDef)
+
+	tokens, err := tkn.tokenise(goalDef)
+
+	if err != nil {
+		return p.err(goalDef, err.Error())
+	}
+
+	for _, t := range tokens {
+
+		if t.kind == token_unknownKeyword {
+			p.warn(goalDef, "Unrecognised keyword '%s' treated as text", t.keyword)
+
+		} else if t.kind != token_text {
+			return p.err(goalDef, "Unexpected %s in goal list", t.kind)
 		}
 
-		for _, t := range tokens {
+		// The goal is the whole line, so it starts with its name
+		goal := &Goal{Name: t.content}
+		goal.Location = goalDef.location(t.column)
+		goal.NameLocation = goalDef.location(t.column)
 
-			if t.kind != token_text {
-				return p.err(goalDef, "Unexpected %s in goal list", t.kind)
-			}
+		goal.Tags = append(goal.Tags, p.pendingTags...)
 
-			goal := &Goal{Name: t.content}
-			goal.Location = &gherkin.Location{
-				Line:   branch.line,
-				Column: branch.column,
-			}
+		p.actor.Goals = append(p.actor.Goals, goal)
+	}
+
+	return nil
+}
+
+func (p *parser) parseAliases(branch *line, t token, tkn *tokeniser) error {
+
+	if p.actor == nil {
+		return p.err(branch, "Aliases keyword outside of actor context")
+	}
 
-			goal.Tags = append(goal.Tags, p.pendingTags...)
+	if p.goal != nil {
+		return p.err(branch, "Aliases keyword inside a goal")
+	}
+
+	if t.content != "" {
+		return p.errAt(branch.location(t.column), "Aliases are listed one per line under the Aliases keyword")
+	}
+
+	if len(p.pendingTags) > 0 {
+		return p.errAt(p.pendingTags[0].Location, "Aliases can't be tagged")
+	}
+
+	// A stream reads the list later
+	if p.streaming {
+		return nil
+	}
 
-			p.actor.Goals = append(p.actor.Goals, goal)
+	for _, aliasDef := range branch.children {
+		if err := p.parseAliasListItem(aliasDef, tkn); err != nil {
+			return err
 		}
 	}
 
-	p.resetTags()
+	return nil
+}
+
+func (p *parser) parseAliasListItem(aliasDef *line, tkn *tokeniser) error {
+
+	p.addComment(aliasDef)
+
+	tokens, err := tkn.tokenise(aliasDef)
+
+	if err != nil {
+		return p.err(aliasDef, err.Error())
+	}
+
+	for _, t := range tokens {
+
+		if t.kind == token_unknownKeyword {
+			p.warn(aliasDef, "Unrecognised keyword '%s' treated as text", t.keyword)
+
+		} else if t.kind != token_text {
+			return p.err(aliasDef, "Unexpected %s in alias list", t.kind)
+		}
+
+		alias := &Alias{Name: t.content}
+		alias.Location = aliasDef.location(t.column)
+
+		// In lenient mode a repeated name is left out, with a warning
+		if repeated, err := p.checkAlias(alias); repeated {
+			return err
+		}
+
+		p.actor.Aliases = append(p.actor.Aliases, alias)
+	}
 
 	return nil
 }
 
+// checkAlias makes sure an alias isn't already a name of the actor
+func (p *parser) checkAlias(alias *Alias) (bool, error) {
+
+	if normaliseName(alias.Name) == normaliseName(p.actor.Name) {
+		return true, p.report(alias.Location, "Alias '%s' is the actor's name", alias.Name)
+	}
+
+	for _, other := range p.actor.Aliases {
+		if normaliseName(alias.Name) == normaliseName(other.Name) {
+			return true, p.report(alias.Location, "Alias '%s' is listed more than once (other alias : [Line %04d:%02d])", alias.Name, other.Location.Line, other.Location.Column)
+		}
+	}
+
+	return false, nil
+}
+
 func (p *parser) parseText(branch *line, t token, tkn *tokeniser) error {
 
 	if p.actor == nil {
@@ -229,3 +583,90 @@ func (p *parser) parseText(branch *line, t token, tkn *tokeniser) error {
 
 	return p.parseTree(branch.children, tkn)
 }
+
+func (p *parser) parseDocString(branch *line, t token) error {
+
+	if p.actor == nil {
+		return p.err(branch, "Doc string outside of actor context")
+	}
+
+	target, owner := &p.actor.DocString, "actor"
+
+	if p.goal != nil {
+		target, owner = &p.goal.DocString, "goal"
+	}
+
+	if *target != nil {
+		return p.err(branch, "Only one doc string is permitted per %s (other doc string : [Line %04d:%02d])", owner, (*target).Location.Line, (*target).Location.Column)
+	}
+
+	delimiter := branch.content.docStringDelimiter()
+	escaped := strings.Repeat(`\`+delimiter[:1], len(delimiter))
+
+	*target = &DocString{
+		ContentType: strings.TrimSpace(t.content),
+		Content:     strings.Replace(strings.Join(branch.verbatim, "\n"), escaped, delimiter, -1),
+		Delimiter:   delimiter,
+	}
+
+	(*target).Location = branch.location(0)
+
+	return nil
+}
+
+func (p *parser) parseTableRow(branch *line) error {
+
+	if p.actor == nil {
+		return p.err(branch, "Table row outside of actor context")
+	}
+
+	if len(branch.children) > 0 {
+		return p.err(branch.children[0], "Unexpected indentation after a table row")
+	}
+
+	target, owner := &p.actor.DataTable, "actor"
+
+	if p.goal != nil {
+		target, owner = &p.goal.DataTable, "goal"
+	}
+
+	if p.table == nil {
+
+		if *target != nil {
+			return p.err(branch, "Only one data table is permitted per %s (other data table : [Line %04d:%02d])", owner, (*target).Location.Line, (*target).Location.Column)
+		}
+
+		p.table = &DataTable{Rows: make([]*TableRow, 0)}
+		p.table.Location = branch.location(0)
+
+		*target = p.table
+	}
+
+	row := &TableRow{Cells: make([]*TableCell, 0)}
+	row.Location = branch.location(0)
+
+	p.table.Rows = append(p.table.Rows, row)
+
+	return nil
+}
+
+func (p *parser) parseTableCell(branch *line, t token) {
+
+	row := p.table.Rows[len(p.table.Rows)-1]
+
+	cell := &TableCell{Value: t.content}
+	cell.Location = branch.location(t.column)
+
+	row.Cells = append(row.Cells, cell)
+}
+
+func (p *parser) checkTableRow(branch *line) error {
+
+	first, last := p.table.Rows[0], p.table.Rows[len(p.table.Rows)-1]
+
+	if len(last.Cells) != len(first.Cells) {
+		return p.err(branch, "Inconsistent cell count within the table (expected %d, found %d)", len(first.Cells), len(last.Cells))
+	}
+
+	return nil
+}
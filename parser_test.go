@@ -80,19 +80,19 @@ Actor: Valid actor
 		},
 		{
 			file: `@tag @ tag`,
-			err:  fmt.Errorf("[Line 0001:00] Tag '@' (#2 on the line) is not valid"),
+			err:  fmt.Errorf("[Line 0001:01] Tag '@' (#2 on the line) is not valid"),
 		},
 
 		{
 			file: `Actor:`,
-			err:  fmt.Errorf("[Line 0001:00] Actor keyword must be followed by an actor name"),
+			err:  fmt.Errorf("[Line 0001:01] Actor keyword must be followed by an actor name"),
 		},
 
 		{
 			file: `
 Actor: Some actor
 Actor: Some other actor`,
-			err: fmt.Errorf("[Line 0003:00] Only one actor definition is permitted per file (other actor 'Some actor' : [Line 0002:00])"),
+			err: fmt.Errorf("[Line 0003:01] Only one actor definition is permitted per file (other actor 'Some actor' : [Line 0002:01])"),
 		},
 	}
 
@@ -108,3 +108,442 @@ Actor: Some other actor`,
 		}
 	}
 }
+
+func Test_ItCanParseALocalisedActorFile(t *testing.T) {
+
+	file := `# language: fr
+@tag1
+Acteur: Administrateur
+    Gère le site
+
+    Objectifs:
+        Publier un article
+
+    @tag2
+    Objectif: Modérer les commentaires
+`
+
+	actor, err := NewParser(bytes.NewBufferString(file)).Parse()
+	assert.Nil(t, err)
+
+	assert.Equal(t, "fr", actor.Language)
+
+	compareActors(t, &Actor{
+		Name:  "Administrateur",
+		Tags:  []*gherkin.Tag{{Name: "tag1"}},
+		Blurb: []string{"Gère le site"},
+		Goals: []*Goal{
+			{Name: "Publier un article"},
+			{Name: "Modérer les commentaires", Tags: []*gherkin.Tag{{Name: "tag2"}}},
+		},
+	}, actor)
+}
+
+func Test_ItRejectsAnUnsupportedLanguage(t *testing.T) {
+
+	actor, err := NewParser(bytes.NewBufferString("# language: xx\nActor: Some actor")).Parse()
+
+	assert.Nil(t, actor)
+	assert.Equal(t, fmt.Errorf("[Line 0001:01] Unsupported language 'xx'"), err)
+}
+
+func Test_ALanguageHeaderAfterTheActorIsAComment(t *testing.T) {
+
+	actor, err := NewParser(bytes.NewBufferString("Actor: Some actor\n    # language: fr\n    Goal: Some goal")).Parse()
+
+	assert.Nil(t, err)
+	assert.Equal(t, "en", actor.Language)
+	assert.Equal(t, "Some goal", actor.Goals[0].Name)
+}
+
+func Test_ItCanParseDocStrings(t *testing.T) {
+
+	file := `Actor: Documented actor
+    Blurb line
+    """markdown
+    Likes:
+
+    * lists
+      * nested lists
+    """
+
+    Goal: Write code
+        ` + "```" + `
+        func main() {
+            "\"\"\"" ` + "`" + `
+        }
+        ` + "```" + `
+
+    Goals:
+        Plain goal
+`
+
+	actor, err := NewParser(bytes.NewBufferString(file)).Parse()
+	assert.Nil(t, err)
+
+	compareActors(t, &Actor{
+		Name:  "Documented actor",
+		Blurb: []string{"Blurb line"},
+		DocString: &DocString{
+			ContentType: "markdown",
+			Content:     "Likes:\n\n* lists\n  * nested lists",
+		},
+		Goals: []*Goal{
+			{
+				Name: "Write code",
+				DocString: &DocString{
+					Content: "func main() {\n    \"\\\"\\\"\\\"\" `\n}",
+				},
+			},
+			{Name: "Plain goal"},
+		},
+	}, actor)
+
+	assert.Equal(t, `"""`, actor.DocString.Delimiter)
+	assert.Equal(t, 3, actor.DocString.Location.Line)
+	assert.Equal(t, "```", actor.Goals[0].DocString.Delimiter)
+}
+
+func Test_ItRejectsMisplacedDocStrings(t *testing.T) {
+
+	var inputs = []struct {
+		file string
+		err  error
+	}{
+		{
+			file: "\"\"\"\ntext\n\"\"\"",
+			err:  fmt.Errorf("[Line 0001:01] Doc string outside of actor context"),
+		},
+		{
+			file: "Actor: Some actor\n    \"\"\"\n    one\n    \"\"\"\n    \"\"\"\n    two\n    \"\"\"",
+			err:  fmt.Errorf("[Line 0005:05] Only one doc string is permitted per actor (other doc string : [Line 0002:05])"),
+		},
+		{
+			file: "Actor: Some actor\n    \"\"\"",
+			err:  fmt.Errorf("Lexer error: [Line 0002:05] Doc string is not closed"),
+		},
+	}
+
+	for _, input := range inputs {
+		actor, err := NewParser(bytes.NewBufferString(input.file)).Parse()
+		assert.Nil(t, actor)
+		assert.Equal(t, input.err, err)
+	}
+}
+
+func Test_ItCanParseAliases(t *testing.T) {
+
+	file := `@staff
+Actor: Site administrator
+    Aliases:
+        Admin # the usual
+        Site  admin
+    Manages the site
+
+    Goal: Moderate comments
+`
+
+	actor, err := NewParser(bytes.NewBufferString(file)).Parse()
+
+	assert.Nil(t, err)
+	assert.Equal(t, []*Alias{
+		{Node: gherkin.Node{Location: &gherkin.Location{Line: 4, Column: 9}}, Name: "Admin"},
+		{Node: gherkin.Node{Location: &gherkin.Location{Line: 5, Column: 9}}, Name: "Site  admin"},
+	}, actor.Aliases)
+	assert.Equal(t, []string{"Manages the site"}, actor.Blurb)
+	assert.Equal(t, "Moderate comments", actor.Goals[0].Name)
+	assert.Equal(t, []string{"Site administrator", "Admin", "Site  admin"}, actor.Names())
+	assert.True(t, actor.IsNamed("site admin"))
+	assert.True(t, actor.IsNamed("SITE ADMINISTRATOR"))
+	assert.False(t, actor.IsNamed("Administrator"))
+}
+
+func Test_ItRejectsMisplacedAliases(t *testing.T) {
+
+	var inputs = []struct {
+		file string
+		err  error
+	}{
+		{
+			file: "Aliases:\n    Admin\n",
+			err:  fmt.Errorf("[Line 0001:01] Aliases keyword outside of actor context"),
+		},
+		{
+			file: "Actor: Some actor\n    Goal: Something\n        Aliases:\n            Admin\n",
+			err:  fmt.Errorf("[Line 0003:09] Aliases keyword inside a goal"),
+		},
+		{
+			file: "Actor: Some actor\n    Aliases: Admin\n",
+			err:  fmt.Errorf("[Line 0002:14] Aliases are listed one per line under the Aliases keyword"),
+		},
+		{
+			file: "Actor: Some actor\n    @admin\n    Aliases:\n        Admin\n",
+			err:  fmt.Errorf("[Line 0002:05] Aliases can't be tagged"),
+		},
+		{
+			file: "Actor: Some actor\n    Aliases:\n        @admin\n",
+			err:  fmt.Errorf("[Line 0003:09] Unexpected token_tag in alias list"),
+		},
+		{
+			file: "Actor: Some actor\n    Aliases:\n        Some  Actor\n",
+			err:  fmt.Errorf("[Line 0003:09] Alias 'Some  Actor' is the actor's name"),
+		},
+		{
+			file: "Actor: Some actor\n    Aliases:\n        Admin\n        admin\n",
+			err:  fmt.Errorf("[Line 0004:09] Alias 'admin' is listed more than once (other alias : [Line 0003:09])"),
+		},
+	}
+
+	for _, input := range inputs {
+		actor, err := NewParser(bytes.NewBufferString(input.file)).Parse()
+		assert.Nil(t, actor)
+		assert.Equal(t, input.err, err, input.file)
+	}
+}
+
+func Test_LenientModeLeavesOutRepeatedAliases(t *testing.T) {
+
+	parser := NewParserWithOptions(bytes.NewBufferString("Actor: Some actor\n    Aliases:\n        Admin\n        admin\n"), ParserOptions{Mode: ModeLenient})
+	actor, err := parser.Parse()
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Some actor", "Admin"}, actor.Names())
+	assert.Equal(t, []*Diagnostic{
+		newDiagnostic(SeverityWarning, 4, 9, "Alias 'admin' is listed more than once (other alias : [Line 0003:09])"),
+	}, parser.Diagnostics())
+}
+
+func Test_ItCanParseDataTables(t *testing.T) {
+
+	file := `Actor: Commuter
+    | device | frequency |
+    | phone  | daily     |
+
+    Goal: Check train times
+        | when    |
+        | morning |
+        | evening |
+`
+
+	actor, err := NewParser(bytes.NewBufferString(file)).Parse()
+	assert.Nil(t, err)
+
+	compareActors(t, &Actor{
+		Name:      "Commuter",
+		Blurb:     []string{},
+		DataTable: newDataTable([]string{"device", "frequency"}, []string{"phone", "daily"}),
+		Goals: []*Goal{
+			{
+				Name:      "Check train times",
+				DataTable: newDataTable([]string{"when"}, []string{"morning"}, []string{"evening"}),
+			},
+		},
+	}, actor)
+
+	assert.Equal(t, &gherkin.Location{Line: 2, Column: 5}, actor.DataTable.Location)
+	assert.Equal(t, &gherkin.Location{Line: 3, Column: 5}, actor.DataTable.Rows[1].Location)
+	assert.Equal(t, &gherkin.Location{Line: 3, Column: 16}, actor.DataTable.Rows[1].Cells[1].Location)
+}
+
+func Test_ItRejectsInvalidDataTables(t *testing.T) {
+
+	var inputs = []struct {
+		file string
+		err  error
+	}{
+		{
+			file: "| a |",
+			err:  fmt.Errorf("[Line 0001:01] Table row outside of actor context"),
+		},
+		{
+			file: "Actor: Some actor\n    | a | b |\n    | c |",
+			err:  fmt.Errorf("[Line 0003:05] Inconsistent cell count within the table (expected 2, found 1)"),
+		},
+		{
+			file: "Actor: Some actor\n    | a |\n    Blurb\n    | b |",
+			err:  fmt.Errorf("[Line 0004:05] Only one data table is permitted per actor (other data table : [Line 0002:05])"),
+		},
+		{
+			file: "Actor: Some actor\n    | a |\n        | b |",
+			err:  fmt.Errorf("[Line 0003:09] Unexpected indentation after a table row"),
+		},
+	}
+
+	for _, input := range inputs {
+		actor, err := NewParser(bytes.NewBufferString(input.file)).Parse()
+		assert.Nil(t, actor)
+		assert.Equal(t, input.err, err)
+	}
+}
+
+func Test_ParseErrorsAreAlsoDiagnostics(t *testing.T) {
+
+	parser := NewParser(bytes.NewBufferString("Actor: Some actor\n    Unknown: keyword"))
+	actor, err := parser.Parse()
+
+	assert.Nil(t, actor)
+	assert.Equal(t, fmt.Errorf("[Line 0002:05] Unrecognised keyword 'Unknown'"), err)
+	assert.Equal(t, []*Diagnostic{
+		newDiagnostic(SeverityError, 2, 5, "Unrecognised keyword 'Unknown'"),
+	}, parser.Diagnostics())
+}
+
+func Test_LenientModeTreatsUnknownKeywordsAsText(t *testing.T) {
+
+	file := `Actor: Some actor
+    Motto: Move fast
+    Goals:
+        Todo: Ship it
+`
+
+	parser := NewParserWithOptions(bytes.NewBufferString(file), ParserOptions{Mode: ModeLenient})
+	actor, err := parser.Parse()
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Motto: Move fast"}, actor.Blurb)
+	assert.Equal(t, "Todo: Ship it", actor.Goals[0].Name)
+
+	assert.Equal(t, []*Diagnostic{
+		newDiagnostic(SeverityWarning, 2, 5, "Unrecognised keyword 'Motto' treated as text"),
+		newDiagnostic(SeverityWarning, 4, 9, "Unrecognised keyword 'Todo' treated as text"),
+	}, parser.Diagnostics())
+}
+
+func Test_StrictModeRejectsInconsistentIndentation(t *testing.T) {
+
+	var inputs = []struct {
+		file string
+		err  error
+	}{
+		{
+			file: "Actor: Some actor\n    Blurb\n    Goals:\n        Goal 1",
+		},
+		{
+			file: "Actor: Some actor\n\tBlurb\n\tGoals:\n\t\tGoal 1",
+		},
+		{
+			file: "Actor: Some actor\n \tBlurb",
+			err:  fmt.Errorf("Lexer error: [Line 0002:03] Indentation mixes tabs and spaces"),
+		},
+		{
+			file: "Actor: Some actor\n    Blurb\n\tMore blurb",
+			err:  fmt.Errorf("Lexer error: [Line 0003:02] Indentation uses tabs, but line 2 uses spaces"),
+		},
+		{
+			file: "Actor: Some actor\n    Goals:\n      Goal 1",
+			err:  fmt.Errorf("Lexer error: [Line 0003:07] Inconsistent indentation: indented by 2, but line 2 is indented by 4"),
+		},
+	}
+
+	for _, input := range inputs {
+
+		_, err := NewParserWithOptions(bytes.NewBufferString(input.file), ParserOptions{Mode: ModeStrict}).Parse()
+		assert.Equal(t, input.err, err)
+
+		// Only strict mode is this fussy
+		_, err = NewParser(bytes.NewBufferString(input.file)).Parse()
+		assert.Nil(t, err)
+	}
+}
+
+func Test_LocationsPointAtEachPartOfTheLine(t *testing.T) {
+
+	file := `@tag1   @tag2
+Actor:   Located actor
+    @tag3
+    Goals:
+        Listed goal
+
+    Goal: Keyword goal
+`
+
+	actor, err := NewParser(bytes.NewBufferString(file)).Parse()
+	assert.Nil(t, err)
+
+	assert.Equal(t, &gherkin.Location{Line: 1, Column: 1}, actor.Tags[0].Location)
+	assert.Equal(t, &gherkin.Location{Line: 1, Column: 9}, actor.Tags[1].Location)
+	assert.Equal(t, &gherkin.Location{Line: 2, Column: 1}, actor.Location)
+	assert.Equal(t, &gherkin.Location{Line: 2, Column: 10}, actor.NameLocation)
+
+	assert.Equal(t, &gherkin.Location{Line: 3, Column: 5}, actor.Goals[0].Tags[0].Location)
+	assert.Equal(t, &gherkin.Location{Line: 5, Column: 9}, actor.Goals[0].Location)
+	assert.Equal(t, &gherkin.Location{Line: 5, Column: 9}, actor.Goals[0].NameLocation)
+
+	assert.Equal(t, &gherkin.Location{Line: 7, Column: 5}, actor.Goals[1].Location)
+	assert.Equal(t, &gherkin.Location{Line: 7, Column: 11}, actor.Goals[1].NameLocation)
+}
+
+func Test_ItKeepsCommentsWithTheirLocations(t *testing.T) {
+
+	file := `# language: en
+# About this actor
+Actor: Commented actor # trailing
+    Reach \#1 in search
+    | a # b |
+    Goals:
+        # A goal
+        Some goal
+`
+
+	actor, err := NewParser(bytes.NewBufferString(file)).Parse()
+	assert.Nil(t, err)
+
+	texts := make([]string, 0)
+	locations := make([]*gherkin.Location, 0)
+
+	for _, comment := range actor.Comments {
+		texts = append(texts, comment.Text)
+		locations = append(locations, comment.Location)
+	}
+
+	assert.Equal(t, []string{"# About this actor", "# trailing", "# A goal"}, texts)
+	assert.Equal(t, []*gherkin.Location{{Line: 2, Column: 1}, {Line: 3, Column: 24}, {Line: 7, Column: 9}}, locations)
+}
+
+func Test_ItChecksTagsAgainstTheRegistry(t *testing.T) {
+
+	registry, err := NewTagRegistry(
+		&TagDefinition{Name: "admin", Scope: []TagScope{TagScopeActor}},
+		&TagDefinition{Name: "critical", Scope: []TagScope{TagScopeGoal}},
+		&TagDefinition{Name: "wip", Deprecated: true, ReplacedBy: "draft"},
+		&TagDefinition{Name: "draft"},
+	)
+	assert.Nil(t, err)
+
+	for _, test := range []struct {
+		file        string
+		mode        Mode
+		err         string
+		diagnostics []string
+	}{
+		{"@admin @wip\nActor: A\n    @critical @draft\n    Goal: B\n", ModeDefault, "", []string{
+			"[Line 0001:08] warning: Tag '@wip' is deprecated, use '@draft' instead",
+		}},
+		{"@admn\nActor: A\n", ModeDefault, "[Line 0001:01] Unknown tag '@admn', did you mean '@admin'?", nil},
+		{"@ADMIN\nActor: A\n", ModeDefault, "[Line 0001:01] Unknown tag '@ADMIN', did you mean '@admin'?", nil},
+		{"@elephant\nActor: A\n", ModeDefault, "[Line 0001:01] Unknown tag '@elephant'", nil},
+		{"Actor: A\n    @admin\n    Goals:\n        B\n", ModeDefault, "[Line 0002:05] Tag '@admin' can't be used on a goal", nil},
+		{"@critical\nActor: A\n", ModeDefault, "[Line 0001:01] Tag '@critical' can't be used on an actor", nil},
+		{"@admn @critical\nActor: A\n", ModeLenient, "", []string{
+			"[Line 0001:01] warning: Unknown tag '@admn', did you mean '@admin'?",
+			"[Line 0001:07] warning: Tag '@critical' can't be used on an actor",
+		}},
+	} {
+		parser := NewParserWithOptions(bytes.NewBufferString(test.file), ParserOptions{Mode: test.mode, Tags: registry})
+		_, err := parser.Parse()
+
+		if test.err == "" {
+			assert.Nil(t, err, test.file)
+
+			diagnostics := make([]string, 0)
+
+			for _, d := range parser.Diagnostics() {
+				diagnostics = append(diagnostics, d.String())
+			}
+
+			assert.Equal(t, test.diagnostics, diagnostics, test.file)
+		} else {
+			assert.Equal(t, test.err, err.Error(), test.file)
+		}
+	}
+}
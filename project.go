@@ -0,0 +1,150 @@
+package actor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// ProjectFile is a .actor file within a project. Files that fail to parse are
+// kept, with the error, so they can be reported alongside the others.
+type ProjectFile struct {
+	Path        string
+	Actor       *Actor
+	Diagnostics []*Diagnostic
+	Err         error
+}
+
+// Project is every .actor file found under a directory
+type Project struct {
+	Root  string
+	Files []*ProjectFile
+
+	// Tags is the tag registry the files were parsed with, if any
+	Tags *TagRegistry
+}
+
+// LoadProject parses every .actor file under root. Only a failure to read the
+// directory, or its tag registry, is returned as an error; parse errors are
+// kept on each file.
+//
+// Unless the options have a tag registry, the project's own
+// DefaultTagRegistryFile is used if there is one.
+func LoadProject(root string, options ParserOptions) (*Project, error) {
+
+	if root == "" {
+		root = "."
+	}
+
+	// Files are parsed from their paths, rather than through the file
+	// system, so that WriteToFile can tell if they've changed since
+	project, err := loadProjectFS(os.DirFS(root), ".", options,
+		func(name string) string {
+			return filepath.Join(root, filepath.FromSlash(name))
+		},
+		LoadProjectFile)
+
+	if err != nil {
+		return nil, err
+	}
+
+	project.Root = root
+
+	return project, nil
+}
+
+// LoadProjectFile parses a single file, as LoadProject does for each file
+func LoadProjectFile(path string, options ParserOptions) *ProjectFile {
+
+	file := &ProjectFile{Path: path}
+
+	parser, err := NewFileParserWithOptions(path, options)
+
+	if err != nil {
+		file.Err = err
+		return file
+	}
+
+	file.Actor, file.Err = parser.Parse()
+	file.Diagnostics = parser.Diagnostics()
+
+	return file
+}
+
+// Actors returns the actors of every file that parsed
+func (p *Project) Actors() []*Actor {
+
+	actors := make([]*Actor, 0, len(p.Files))
+
+	for _, file := range p.Files {
+		if file.Actor != nil {
+			actors = append(actors, file.Actor)
+		}
+	}
+
+	return actors
+}
+
+// FindActor returns the file defining the actor with a name or alias,
+// ignoring case and differences in whitespace, or nil
+func (p *Project) FindActor(name string) *ProjectFile {
+
+	for _, file := range p.Files {
+		if file.Actor != nil && file.Actor.IsNamed(name) {
+			return file
+		}
+	}
+
+	return nil
+}
+
+// FindActorByTag returns the file defining the actor that a tag names, such
+// as @site-admin for an actor or alias "Site admin", or nil
+func (p *Project) FindActorByTag(tag string) *ProjectFile {
+
+	name := strings.Map(func(r rune) rune {
+		if r == '-' || r == '_' {
+			return ' '
+		}
+
+		return r
+	}, strings.TrimPrefix(tag, "@"))
+
+	return p.FindActor(name)
+}
+
+// SuggestActor returns the file defining the actor whose name or alias is
+// closest to a name that isn't an actor, or nil if none is close
+func (p *Project) SuggestActor(name string) *ProjectFile {
+
+	name = normaliseName(name)
+
+	var best *ProjectFile
+	bestDistance := 0
+
+	// Allow about one mistake for every three characters, as for tags
+	limit := utf8.RuneCountInString(name)/3 + 1
+
+	for _, file := range p.Files {
+
+		if file.Actor == nil {
+			continue
+		}
+
+		for _, other := range file.Actor.Names() {
+
+			distance := levenshtein(name, normaliseName(other))
+
+			if distance <= limit && (best == nil || distance < bestDistance) {
+				best, bestDistance = file, distance
+			}
+		}
+	}
+
+	return best
+}
+
+func normaliseName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
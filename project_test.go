@@ -0,0 +1,92 @@
+package actor
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestProject(t *testing.T, files map[string]string) string {
+
+	dir := t.TempDir()
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		assert.Nil(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.Nil(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+
+	return dir
+}
+
+func Test_ItLoadsEveryActorInAProject(t *testing.T) {
+
+	dir := newTestProject(t, map[string]string{
+		"admin.actor":          "Actor: Site  Administrator\n    Aliases:\n        Site admin\n    Goal: Moderate\n",
+		"people/visitor.actor": "Actor: Visitor\n",
+		"people/broken.actor":  "Actor:\n",
+		".hidden/skip.actor":   "Actor: Hidden\n",
+		"notes.txt":            "Not an actor",
+	})
+
+	project, err := LoadProject(dir, ParserOptions{})
+	assert.Nil(t, err)
+
+	assert.Equal(t, 3, len(project.Files))
+	assert.Equal(t, filepath.Join(dir, "admin.actor"), project.Files[0].Path)
+	assert.Equal(t, filepath.Join(dir, "people/broken.actor"), project.Files[1].Path)
+	assert.NotNil(t, project.Files[1].Err)
+	assert.Equal(t, 1, len(project.Files[1].Diagnostics))
+
+	assert.Equal(t, 2, len(project.Actors()))
+
+	// The files are parsed from the directory, so writing back over one that
+	// has changed since is refused
+	visitor := project.Files[2]
+	assert.Nil(t, ioutil.WriteFile(visitor.Path, []byte("Actor: Guest\n"), 0644))
+	assert.Equal(t, ErrFileChanged, visitor.Actor.WriteToFile(visitor.Path))
+
+	found := project.FindActor("site administrator")
+	assert.NotNil(t, found)
+	assert.Equal(t, "Site  Administrator", found.Actor.Name)
+	assert.Nil(t, project.FindActor("Hidden"))
+	assert.Equal(t, found, project.FindActor("SITE  ADMIN"))
+	assert.Equal(t, found, project.FindActorByTag("@site-admin"))
+	assert.Equal(t, found, project.FindActorByTag("site_administrator"))
+	assert.Nil(t, project.FindActorByTag("@admin"))
+
+	assert.Equal(t, found, project.SuggestActor("Site Admnistrator"))
+	assert.Equal(t, found, project.SuggestActor("Site admn"))
+	assert.Nil(t, project.SuggestActor("Site"))
+}
+
+func Test_LoadingAMissingProjectFails(t *testing.T) {
+	project, err := LoadProject("does/not/exist", ParserOptions{})
+	assert.Nil(t, project)
+	assert.True(t, os.IsNotExist(err))
+	assert.Contains(t, err.Error(), "does/not/exist")
+}
+
+func Test_ItUsesTheProjectsTagRegistry(t *testing.T) {
+
+	dir := newTestProject(t, map[string]string{
+		DefaultTagRegistryFile: `{"tags": [{"name": "admin"}]}`,
+		"admin.actor":          "@admin\nActor: Admin\n",
+		"typo.actor":           "@admn\nActor: Typo\n",
+	})
+
+	project, err := LoadProject(dir, ParserOptions{})
+	assert.Nil(t, err)
+
+	assert.NotNil(t, project.Tags)
+	assert.Nil(t, project.Files[0].Err)
+	assert.Equal(t, "[Line 0001:01] Unknown tag '@admn', did you mean '@admin'?", project.Files[1].Err.Error())
+
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, DefaultTagRegistryFile), []byte(`{"tags": 1}`), 0644))
+
+	_, err = LoadProject(dir, ParserOptions{})
+	assert.Equal(t, filepath.Join(dir, DefaultTagRegistryFile)+": Invalid tag registry: json: cannot unmarshal number into Go struct field TagRegistry.tags of type []*actor.TagDefinition", err.Error())
+}
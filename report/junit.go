@@ -0,0 +1,97 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dryvercorp/actor"
+)
+
+// JUnit XML, as read by CI systems, with a test case for each file. A file
+// fails when it has errors; its warnings are written to the case's output.
+
+type junitTestSuites struct {
+	XMLName  xml.Name          `xml:"testsuites"`
+	Name     string            `xml:"name,attr"`
+	Tests    int               `xml:"tests,attr"`
+	Failures int               `xml:"failures,attr"`
+	Suites   []*junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Cases    []*junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+func (r *Report) WriteJUnit(w io.Writer) error {
+
+	suite := &junitTestSuite{Name: r.Tool, Cases: make([]*junitTestCase, 0, len(r.Files))}
+	byPath := r.findings()
+
+	for _, path := range r.Files {
+
+		c := &junitTestCase{ClassName: r.Tool, Name: path}
+		errors, warnings := make([]string, 0), make([]string, 0)
+
+		for _, f := range byPath[path] {
+			if f.Severity == actor.SeverityError {
+				errors = append(errors, f.String())
+			} else {
+				warnings = append(warnings, f.String())
+			}
+		}
+
+		if len(errors) > 0 {
+			c.Failure = &junitFailure{
+				Message: fmt.Sprintf("%d errors", len(errors)),
+				Type:    actor.SeverityError.String(),
+				Text:    strings.Join(errors, "\n"),
+			}
+
+			if len(errors) == 1 {
+				c.Failure.Message = "1 error"
+			}
+
+			suite.Failures++
+		}
+
+		c.SystemOut = strings.Join(warnings, "\n")
+		suite.Cases = append(suite.Cases, c)
+	}
+
+	suite.Tests = len(suite.Cases)
+
+	suites := &junitTestSuites{Name: r.Tool, Tests: suite.Tests, Failures: suite.Failures, Suites: []*junitTestSuite{suite}}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+
+	if err := encoder.Encode(suites); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
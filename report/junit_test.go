@@ -0,0 +1,33 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ItWritesJUnit(t *testing.T) {
+
+	r := newTestReport(t)
+
+	buf := &bytes.Buffer{}
+	assert.Nil(t, r.WriteJUnit(buf))
+
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="actor test" tests="4" failures="2">
+  <testsuite name="actor test" tests="4" failures="2">
+    <testcase classname="actor test" name="actors/admin.actor">
+      <system-out>actors/admin.actor:3:2: warning: Indentation mixes tabs and spaces (syntax)&#xA;actors/admin.actor:1:8: warning: Actor name &#39;admin&#39; should start with a capital letter (actor-name-case)</system-out>
+    </testcase>
+    <testcase classname="actor test" name="actors/bad.actor">
+      <failure message="1 error" type="error">actors/bad.actor:2:5: error: Unrecognised keyword &#39;Motto&#39; (syntax)</failure>
+    </testcase>
+    <testcase classname="actor test" name="actors/missing.actor">
+      <failure message="1 error" type="error">actors/missing.actor: error: open actors/missing.actor: no such file or directory (syntax)</failure>
+    </testcase>
+    <testcase classname="actor test" name="actors/good.actor"></testcase>
+  </testsuite>
+</testsuites>
+`, buf.String())
+}
@@ -0,0 +1,152 @@
+// Package report writes the problems found in .actor files, by the parser and
+// by lint rules, in the formats that CI systems and code scanning dashboards
+// read: SARIF 2.1.0 and JUnit XML, as well as text and JSON.
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	gherkin "github.com/cucumber/gherkin-go"
+	"github.com/dryvercorp/actor"
+	"github.com/dryvercorp/actor/lint"
+	"github.com/dryvercorp/actor/narrative"
+)
+
+// SyntaxRule is the rule of the problems found by the parser
+const SyntaxRule = "syntax"
+
+// NarrativeRule is the rule of features whose narrative doesn't name an actor
+const NarrativeRule = "narrative"
+
+// Finding is a problem found in a file. The location is nil when the file
+// couldn't be read.
+type Finding struct {
+	Path     string            `json:"path"`
+	Rule     string            `json:"rule"`
+	Location *gherkin.Location `json:"location,omitempty"`
+	Severity actor.Severity    `json:"severity"`
+	Message  string            `json:"message"`
+}
+
+func (f *Finding) String() string {
+
+	if f.Location == nil {
+		return fmt.Sprintf("%s: %s: %s (%s)", f.Path, f.Severity, f.Message, f.Rule)
+	}
+
+	return fmt.Sprintf("%s:%d:%d: %s: %s (%s)", f.Path, f.Location.Line, f.Location.Column, f.Severity, f.Message, f.Rule)
+}
+
+type Rule struct {
+	ID          string
+	Description string
+}
+
+// Report is everything found by a tool across a set of files
+type Report struct {
+	Tool     string
+	Rules    []*Rule
+	Files    []string // Every file checked, with or without findings
+	Findings []*Finding
+}
+
+// New returns a report of the parser's diagnostics for every file of the
+// project
+func New(tool string, project *actor.Project) *Report {
+
+	r := &Report{
+		Tool:     tool,
+		Rules:    []*Rule{{ID: SyntaxRule, Description: "The file parses"}},
+		Files:    make([]string, 0, len(project.Files)),
+		Findings: make([]*Finding, 0),
+	}
+
+	for _, file := range project.Files {
+
+		r.Files = append(r.Files, file.Path)
+
+		for _, d := range file.Diagnostics {
+			r.Findings = append(r.Findings, &Finding{Path: file.Path, Rule: SyntaxRule, Location: d.Location, Severity: d.Severity, Message: d.Message})
+		}
+
+		// A file that couldn't be read has no diagnostics to explain it
+		if file.Err != nil && len(file.Diagnostics) == 0 {
+			r.Findings = append(r.Findings, &Finding{Path: file.Path, Rule: SyntaxRule, Severity: actor.SeverityError, Message: file.Err.Error()})
+		}
+	}
+
+	return r
+}
+
+// AddLint adds the linter's rules and the problems it found
+func (r *Report) AddLint(linter *lint.Linter, results []*lint.Result) {
+
+	for _, rule := range linter.Rules() {
+		r.Rules = append(r.Rules, &Rule{ID: rule.Name(), Description: rule.Description()})
+	}
+
+	for _, result := range results {
+		for _, p := range result.Problems {
+			r.Findings = append(r.Findings, &Finding{Path: result.Path, Rule: p.Rule, Location: p.Location, Severity: p.Severity, Message: p.Message})
+		}
+	}
+}
+
+// AddNarratives adds the feature files that were checked, and those whose
+// narrative doesn't name an actor
+func (r *Report) AddNarratives(features []string, mismatches []*narrative.Mismatch) {
+
+	r.Rules = append(r.Rules, &Rule{ID: NarrativeRule, Description: "The feature's narrative names an actor"})
+	r.Files = append(r.Files, features...)
+
+	for _, m := range mismatches {
+		r.Findings = append(r.Findings, &Finding{Path: m.Path, Rule: NarrativeRule, Location: m.Location, Severity: actor.SeverityError, Message: m.Message()})
+	}
+}
+
+// Errors counts the findings that are errors rather than warnings
+func (r *Report) Errors() int {
+
+	errors := 0
+
+	for _, f := range r.Findings {
+		if f.Severity == actor.SeverityError {
+			errors++
+		}
+	}
+
+	return errors
+}
+
+// findings groups the findings by file
+func (r *Report) findings() map[string][]*Finding {
+
+	byPath := make(map[string][]*Finding)
+
+	for _, f := range r.Findings {
+		byPath[f.Path] = append(byPath[f.Path], f)
+	}
+
+	return byPath
+}
+
+func (r *Report) WriteText(w io.Writer) error {
+
+	for _, f := range r.Findings {
+		if _, err := fmt.Fprintln(w, f); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *Report) WriteJSON(w io.Writer) error {
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(r.Findings)
+}
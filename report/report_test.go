@@ -0,0 +1,120 @@
+package report
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	gherkin "github.com/cucumber/gherkin-go"
+	"github.com/dryvercorp/actor"
+	"github.com/dryvercorp/actor/lint"
+	"github.com/dryvercorp/actor/narrative"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestReport(t *testing.T) *Report {
+
+	project := &actor.Project{Files: []*actor.ProjectFile{
+		{
+			Path:  "actors/admin.actor",
+			Actor: &actor.Actor{Name: "admin"},
+			Diagnostics: []*actor.Diagnostic{
+				{Location: &gherkin.Location{Line: 3, Column: 2}, Severity: actor.SeverityWarning, Message: "Indentation mixes tabs and spaces"},
+			},
+		},
+		{
+			Path: "actors/bad.actor",
+			Err:  errors.New("[Line 0002:05] Unrecognised keyword 'Motto'"),
+			Diagnostics: []*actor.Diagnostic{
+				{Location: &gherkin.Location{Line: 2, Column: 5}, Severity: actor.SeverityError, Message: "Unrecognised keyword 'Motto'"},
+			},
+		},
+		{
+			Path: "actors/missing.actor",
+			Err:  errors.New("open actors/missing.actor: no such file or directory"),
+		},
+		{
+			Path:  "actors/good.actor",
+			Actor: &actor.Actor{Name: "Good"},
+		},
+	}}
+
+	r := New("actor test", project)
+
+	linter, err := lint.New(nil)
+	assert.Nil(t, err)
+
+	r.AddLint(linter, []*lint.Result{
+		{Path: "actors/admin.actor", Problems: []*lint.Problem{
+			{Rule: "actor-name-case", Location: &gherkin.Location{Line: 1, Column: 8}, Severity: actor.SeverityWarning, Message: "Actor name 'admin' should start with a capital letter"},
+		}},
+	})
+
+	return r
+}
+
+func Test_ItCollectsFindings(t *testing.T) {
+
+	r := newTestReport(t)
+
+	assert.Equal(t, []string{"actors/admin.actor", "actors/bad.actor", "actors/missing.actor", "actors/good.actor"}, r.Files)
+	assert.Equal(t, SyntaxRule, r.Rules[0].ID)
+	assert.Equal(t, 1+len(lint.DefaultRules()), len(r.Rules))
+	assert.Equal(t, 2, r.Errors())
+
+	buf := &bytes.Buffer{}
+	assert.Nil(t, r.WriteText(buf))
+
+	assert.Equal(t, `actors/admin.actor:3:2: warning: Indentation mixes tabs and spaces (syntax)
+actors/bad.actor:2:5: error: Unrecognised keyword 'Motto' (syntax)
+actors/missing.actor: error: open actors/missing.actor: no such file or directory (syntax)
+actors/admin.actor:1:8: warning: Actor name 'admin' should start with a capital letter (actor-name-case)
+`, buf.String())
+}
+
+func Test_ItAddsNarrativeMismatches(t *testing.T) {
+
+	admin := &actor.ProjectFile{Path: "actors/admin.actor", Actor: &actor.Actor{Node: gherkin.Node{Location: &gherkin.Location{Line: 1, Column: 1}}, Name: "Admin"}}
+
+	r := New("actor narrative", &actor.Project{Files: []*actor.ProjectFile{admin}})
+	r.AddNarratives([]string{"features/a.feature", "features/b.feature"}, []*narrative.Mismatch{
+		{Path: "features/b.feature", Location: &gherkin.Location{Line: 2, Column: 5}, Role: "Admn", Closest: admin},
+	})
+
+	assert.Equal(t, []string{"actors/admin.actor", "features/a.feature", "features/b.feature"}, r.Files)
+	assert.Equal(t, NarrativeRule, r.Rules[1].ID)
+	assert.Equal(t, 1, r.Errors())
+
+	buf := &bytes.Buffer{}
+	assert.Nil(t, r.WriteText(buf))
+	assert.Equal(t, "features/b.feature:2:5: error: 'Admn' is not an actor, did you mean 'Admin' (actors/admin.actor:1:1)? (narrative)\n", buf.String())
+}
+
+func Test_ItWritesJSON(t *testing.T) {
+
+	r := newTestReport(t)
+	r.Findings = r.Findings[1:3]
+
+	buf := &bytes.Buffer{}
+	assert.Nil(t, r.WriteJSON(buf))
+
+	assert.Equal(t, `[
+  {
+    "path": "actors/bad.actor",
+    "rule": "syntax",
+    "location": {
+      "line": 2,
+      "column": 5
+    },
+    "severity": "error",
+    "message": "Unrecognised keyword 'Motto'"
+  },
+  {
+    "path": "actors/missing.actor",
+    "rule": "syntax",
+    "severity": "error",
+    "message": "open actors/missing.actor: no such file or directory"
+  }
+]
+`, buf.String())
+}
@@ -0,0 +1,119 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+
+	"github.com/dryvercorp/actor"
+)
+
+// The subset of SARIF 2.1.0 used to report problems to code scanning tools
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+type sarifLog struct {
+	Schema  string      `json:"$schema"`
+	Version string      `json:"version"`
+	Runs    []*sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool      `json:"tool"`
+	Results []*sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string       `json:"name"`
+	InformationURI string       `json:"informationUri"`
+	Rules          []*sarifRule `json:"rules,omitempty"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string           `json:"ruleId,omitempty"`
+	Level     string           `json:"level"`
+	Message   sarifMessage     `json:"message"`
+	Locations []*sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+}
+
+// WriteSARIF writes the report as a SARIF log with a single run
+func (r *Report) WriteSARIF(w io.Writer) error {
+
+	log := &sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []*sarifRun{
+			{
+				Tool:    sarifTool{Driver: sarifDriver{Name: r.Tool, InformationURI: "https://github.com/dryvercorp/actor"}},
+				Results: make([]*sarifResult, 0, len(r.Findings)),
+			},
+		},
+	}
+
+	run := log.Runs[0]
+
+	for _, rule := range r.Rules {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, &sarifRule{ID: rule.ID, ShortDescription: sarifMessage{Text: rule.Description}})
+	}
+
+	for _, f := range r.Findings {
+
+		level := "error"
+
+		if f.Severity == actor.SeverityWarning {
+			level = "warning"
+		}
+
+		location := &sarifLocation{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(f.Path)}}}
+
+		if f.Location != nil {
+			location.PhysicalLocation.Region = &sarifRegion{StartLine: f.Location.Line, StartColumn: f.Location.Column}
+		}
+
+		run.Results = append(run.Results, &sarifResult{
+			RuleID:    f.Rule,
+			Level:     level,
+			Message:   sarifMessage{Text: f.Message},
+			Locations: []*sarifLocation{location},
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(log)
+}
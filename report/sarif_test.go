@@ -0,0 +1,55 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ItWritesSARIF(t *testing.T) {
+
+	r := newTestReport(t)
+	r.Rules = r.Rules[:1]
+	r.Findings = r.Findings[1:3]
+
+	buf := &bytes.Buffer{}
+	assert.Nil(t, r.WriteSARIF(buf))
+
+	var log sarifLog
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &log))
+
+	assert.Equal(t, sarifLog{
+		Schema:  sarifSchema,
+		Version: "2.1.0",
+		Runs: []*sarifRun{
+			{
+				Tool: sarifTool{Driver: sarifDriver{
+					Name:           "actor test",
+					InformationURI: "https://github.com/dryvercorp/actor",
+					Rules:          []*sarifRule{{ID: "syntax", ShortDescription: sarifMessage{Text: "The file parses"}}},
+				}},
+				Results: []*sarifResult{
+					{
+						RuleID:  "syntax",
+						Level:   "error",
+						Message: sarifMessage{Text: "Unrecognised keyword 'Motto'"},
+						Locations: []*sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+							ArtifactLocation: sarifArtifactLocation{URI: "actors/bad.actor"},
+							Region:           &sarifRegion{StartLine: 2, StartColumn: 5},
+						}}},
+					},
+					{
+						RuleID:  "syntax",
+						Level:   "error",
+						Message: sarifMessage{Text: "open actors/missing.actor: no such file or directory"},
+						Locations: []*sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+							ArtifactLocation: sarifArtifactLocation{URI: "actors/missing.actor"},
+						}}},
+					},
+				},
+			},
+		},
+	}, log)
+}
@@ -0,0 +1,400 @@
+package actor
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	gherkin "github.com/cucumber/gherkin-go"
+)
+
+// StreamHandler receives the parts of an actor as a Stream reads them. Any
+// of the callbacks may be nil, and an error returned by one stops the stream.
+type StreamHandler struct {
+	// OnActor is called once the Actor: line is read, with the actor's
+	// name, tags and language. Its aliases, doc string and data table are
+	// set on the same actor as they are read, but its blurb and goals are
+	// only passed to OnBlurb and OnGoal.
+	OnActor func(a *Actor) error
+
+	// OnTag is called with each tag, before the actor or goal it belongs to
+	OnTag func(tag *gherkin.Tag) error
+
+	// OnGoal is called with each goal once it's complete, in file order
+	OnGoal func(goal *Goal) error
+
+	OnBlurb func(text string, location *gherkin.Location) error
+
+	// OnError is called with each warning, and with the error that stops
+	// the stream
+	OnError func(d *Diagnostic)
+}
+
+// Stream parses an actor line by line, without holding the file or the
+// actor's goals and blurb in memory. The actor is checked as Parse checks
+// it, but problems are reported as they are reached, so of several problems
+// a different one may stop the stream.
+func Stream(r io.Reader, handler StreamHandler) error {
+	return StreamWithOptions(r, ParserOptions{}, handler)
+}
+
+func StreamWithOptions(r io.Reader, options ParserOptions, handler StreamHandler) error {
+
+	p := NewParserWithOptions(nil, options).(*parser)
+	p.streaming = true
+
+	lex := newLexer(r)
+	lex.strict = options.Mode == ModeStrict
+
+	if options.TabWidth > 0 {
+		lex.tabWidth = options.TabWidth
+	}
+
+	tkn := newTokeniser()
+	tkn.lenient = options.Mode == ModeLenient
+
+	s := &streamer{parser: p, lexer: lex, tokeniser: tkn, handler: handler}
+
+	err := s.stream()
+
+	s.flushDiagnostics()
+
+	return err
+}
+
+// How the lines nested under a line are treated
+type streamChildren int
+
+const (
+	streamParse streamChildren = iota
+	streamGoalList
+	streamAliasList
+	streamTableRow
+	streamIgnore
+)
+
+// streamLevel is a line that the following lines may be nested under
+type streamLevel struct {
+	indent   int
+	children streamChildren
+	goal     *Goal // The goal of a Goal: line, which is complete when the level closes
+}
+
+type streamer struct {
+	parser    *parser
+	lexer     *lexer
+	tokeniser *tokeniser
+	handler   StreamHandler
+
+	levels        []*streamLevel
+	started       bool
+	actorReported bool
+	openGoals     map[*Goal]bool
+}
+
+func (s *streamer) stream() error {
+
+	scanner := bufio.NewScanner(s.lexer.reader)
+	scanner.Split(bufio.ScanLines)
+
+	line_number := 0
+
+	var docString *line
+	delimiter := ""
+
+	for scanner.Scan() {
+		line_number++
+
+		// Doc strings are kept exactly as written until they are closed
+		if docString != nil {
+			raw := lineContent(scanner.Text())
+
+			if strings.Trim(string(raw), " \t") == delimiter {
+				docString.end = line_number
+
+				if err := s.parseLine(docString.branch()); err != nil {
+					return err
+				}
+
+				docString = nil
+				continue
+			}
+
+			docString.verbatim = append(docString.verbatim, raw.dedent(docString.content.visualIndent(s.lexer.tabWidth), s.lexer.tabWidth))
+			continue
+		}
+
+		text := strings.TrimRight(scanner.Text(), " \t")
+
+		if text == "" {
+			continue
+		}
+
+		raw_line := newLine(line_number, 0, text)
+
+		if err := s.lexer.checkIndentCharacters(raw_line); err != nil {
+			return fmt.Errorf("Lexer error: %s", err)
+		}
+
+		branch, err := s.nest(raw_line)
+
+		if err != nil {
+			return err
+		}
+
+		// The doc string is parsed once it's closed
+		if delimiter = raw_line.content.docStringDelimiter(); delimiter != "" {
+			docString = raw_line
+			docString.verbatim = make([]string, 0)
+			continue
+		}
+
+		if err := s.parseLine(branch); err != nil {
+			return err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	if docString != nil {
+		return fmt.Errorf("Lexer error: %s", s.lexer.err(docString, "Doc string is not closed"))
+	}
+
+	return s.close(0)
+}
+
+// nest works out where a line is nested as the lexer would, closing the
+// levels the line ends, and returns the line to parse
+func (s *streamer) nest(raw_line *line) (*line, error) {
+
+	indent := raw_line.content.visualIndent(s.lexer.tabWidth)
+	dedented := false
+
+	for len(s.levels) > 0 && s.levels[len(s.levels)-1].indent > indent {
+		if err := s.close(len(s.levels) - 1); err != nil {
+			return nil, err
+		}
+
+		dedented = true
+	}
+
+	if len(s.levels) == 0 && s.started {
+		if err := s.lexer.report(raw_line, "Indentation of width %d is less than the first line's", indent); err != nil {
+			return nil, fmt.Errorf("Lexer error: %s", err)
+		}
+	}
+
+	s.started = true
+
+	if len(s.levels) > 0 {
+
+		parent := s.levels[len(s.levels)-1]
+
+		// Coming back out of a level, the line should have matched one
+		// that already exists
+		if dedented && indent > parent.indent {
+
+			if err := s.lexer.report(raw_line, "Indentation of width %d doesn't match any outer level, so is treated as width %d", indent, parent.indent); err != nil {
+				return nil, fmt.Errorf("Lexer error: %s", err)
+			}
+
+			indent = parent.indent
+
+		} else if indent > parent.indent && s.lexer.strict {
+
+			if err := s.lexer.checkIndentStep(raw_line, indent-parent.indent); err != nil {
+				return nil, fmt.Errorf("Lexer error: %s", err)
+			}
+		}
+
+		if indent == parent.indent {
+			if err := s.close(len(s.levels) - 1); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	s.levels = append(s.levels, &streamLevel{indent: indent, children: streamIgnore})
+
+	return raw_line.branch(), nil
+}
+
+// parseLine parses a line according to the line it's nested under, and
+// reports what it added to the actor
+func (s *streamer) parseLine(branch *line) error {
+
+	p := s.parser
+	level := s.levels[len(s.levels)-1]
+	children := streamParse
+
+	if len(s.levels) > 1 {
+		children = s.levels[len(s.levels)-2].children
+	}
+
+	tags := len(p.pendingTags)
+
+	switch children {
+
+	case streamIgnore:
+		return nil
+
+	case streamTableRow:
+		return s.check(p.err(branch, "Unexpected indentation after a table row"))
+
+	case streamGoalList:
+		if err := s.check(p.parseGoalListItem(branch, s.tokeniser)); err != nil {
+			return err
+		}
+
+		return s.flush()
+
+	case streamAliasList:
+		return s.check(p.parseAliasListItem(branch, s.tokeniser))
+	}
+
+	if err := s.check(p.parseTree(lexerTree{branch}, s.tokeniser)); err != nil {
+		return err
+	}
+
+	// The tokens are kept on the line once it's parsed
+	if len(branch.tokens) > 0 {
+		switch branch.tokens[0].kind {
+
+		case token_actorDefinition, token_text, token_unknownKeyword:
+			level.children = streamParse
+
+		case token_goal:
+			level.children, level.goal = streamParse, p.goal
+
+			if s.openGoals == nil {
+				s.openGoals = make(map[*Goal]bool)
+			}
+
+			s.openGoals[p.goal] = true
+
+		case token_goals:
+			level.children = streamGoalList
+
+		case token_aliases:
+			level.children = streamAliasList
+
+		case token_tableRow:
+			level.children = streamTableRow
+		}
+	}
+
+	if s.handler.OnTag != nil && len(p.pendingTags) > tags {
+		for _, tag := range p.pendingTags[tags:] {
+			if err := s.handler.OnTag(tag); err != nil {
+				return err
+			}
+		}
+	}
+
+	if p.actor != nil && !s.actorReported {
+
+		s.actorReported = true
+
+		if s.handler.OnActor != nil {
+			if err := s.handler.OnActor(p.actor); err != nil {
+				return err
+			}
+		}
+	}
+
+	if p.actor != nil {
+
+		for _, blurb := range p.actor.Blurb {
+			if s.handler.OnBlurb != nil {
+				if err := s.handler.OnBlurb(blurb, branch.location(0)); err != nil {
+					return err
+				}
+			}
+		}
+
+		p.actor.Blurb = p.actor.Blurb[:0]
+	}
+
+	return s.flush()
+}
+
+// check records an error from parsing a line
+func (s *streamer) check(err error) error {
+
+	s.parser.comments = s.parser.comments[:0]
+	s.flushDiagnostics()
+
+	return err
+}
+
+// close ends the level at index and those nested within it
+func (s *streamer) close(index int) error {
+
+	for len(s.levels) > index {
+
+		level := s.levels[len(s.levels)-1]
+		s.levels = s.levels[:len(s.levels)-1]
+
+		switch {
+		case level.goal != nil:
+			s.parser.endGoal()
+			delete(s.openGoals, level.goal)
+
+		case level.children == streamGoalList:
+			s.parser.resetTags()
+		}
+	}
+
+	return s.flush()
+}
+
+// flush passes on the goals that are complete, keeping those still open and
+// any after them so that goals are reported in order
+func (s *streamer) flush() error {
+
+	p := s.parser
+
+	if p.actor == nil {
+		return nil
+	}
+
+	done := 0
+
+	for _, goal := range p.actor.Goals {
+
+		if s.openGoals[goal] {
+			break
+		}
+
+		if s.handler.OnGoal != nil {
+			if err := s.handler.OnGoal(goal); err != nil {
+				return err
+			}
+		}
+
+		done++
+	}
+
+	p.actor.Goals = append(p.actor.Goals[:0], p.actor.Goals[done:]...)
+
+	return nil
+}
+
+func (s *streamer) flushDiagnostics() {
+
+	if s.handler.OnError != nil {
+		for _, d := range s.lexer.diagnostics {
+			s.handler.OnError(d)
+		}
+
+		for _, d := range s.parser.diagnostics {
+			s.handler.OnError(d)
+		}
+	}
+
+	s.lexer.diagnostics = s.lexer.diagnostics[:0]
+	s.parser.diagnostics = s.parser.diagnostics[:0]
+}
@@ -0,0 +1,218 @@
+package actor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"testing"
+
+	gherkin "github.com/cucumber/gherkin-go"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// streamActor puts an actor back together from a stream
+func streamActor(text string, options ParserOptions) (*Actor, []string, error) {
+
+	var actor *Actor
+	blurb, goals, diagnostics := []string{}, []*Goal{}, []string{}
+
+	err := StreamWithOptions(bytes.NewBufferString(text), options, StreamHandler{
+		OnActor: func(a *Actor) error {
+			actor = a
+			return nil
+		},
+		OnGoal: func(goal *Goal) error {
+			goals = append(goals, goal)
+			return nil
+		},
+		OnBlurb: func(text string, location *gherkin.Location) error {
+			blurb = append(blurb, text)
+			return nil
+		},
+		OnError: func(d *Diagnostic) {
+			diagnostics = append(diagnostics, fmt.Sprintf("%d:%d %s %s", d.Location.Line, d.Location.Column, d.Severity, d.Message))
+		},
+	})
+
+	if actor != nil {
+		actor.Goals = goals
+
+		if len(blurb) > 0 {
+			actor.Blurb = blurb
+		}
+	}
+
+	sort.Strings(diagnostics)
+
+	return actor, diagnostics, err
+}
+
+func Test_AStreamFindsWhatParseDoes(t *testing.T) {
+
+	inputs := []string{
+		`
+# This is a comment
+@tag1 @tag2
+Actor: Valid actor
+    Description and blurb... # with a comment
+    Some other line of blurb
+
+    @tag3 @tag4
+    Goals:
+        Goal number 1
+        Goal number 2
+
+    @tag5 @tag6
+    Goal: Goal number 3
+        """markdown
+          * Kept as written
+        """
+        | a | b |
+        | c | d |
+    Goal: Goal number 4
+    | e |
+`,
+		"# language: fr\n@a\nActeur: Administrateur\n    Objectif: Publier\n",
+		"Actor: A\n    Goal: Outer\n        Goal: Inner\n        | a |\n    Goal: Last\n",
+		"Actor: A\n    @t1\n        Ignored: as tags have no children\n    Goals:\n        G1\n            Ignored: too\n        G2\n    Blurb\n        Nested blurb\n",
+		"Actor: A\n        Goal: Deep\n    Goal: Snapped\n  Blurb snapped\n",
+		"Actor: A\n\t  Mixed\n\tTabs\n",
+		"    Actor: A\nBlurb outdented\n",
+		"Actor: A\n    Something: unknown\n    Goals:\n        Else: unknown\n",
+		"Actor: A\n    Goal: Moderate\n        | a | b |\n        | c |\n",
+		"Actor: A\n    | a |\n        indented\n",
+		"Actor: A\n    @tag\n    Goals:\n        @tag G\n",
+		"Actor: A\nActor: B\n",
+		"Actor: A\n    \"\"\"\n    Never closed\n",
+		"Actor: A\n  Goal: Two\n    Goal: Four\n",
+		"Goal: No actor\n",
+		"Actor: A\n    Aliases:\n        B # the usual\n        Other: b\n            Ignored\n    Blurb\n",
+		"Actor: A\n    Aliases:\n        B\n        b\n    Goal: G\n",
+		"Actor: A\n    Goal: G\n        Aliases:\n            B\n",
+		"",
+	}
+
+	for _, mode := range []Mode{ModeDefault, ModeLenient, ModeStrict} {
+		for _, input := range inputs {
+
+			options := ParserOptions{Mode: mode}
+			parser := NewParserWithOptions(bytes.NewBufferString(input), options)
+
+			expected, expected_err := parser.Parse()
+			actual, diagnostics, err := streamActor(input, options)
+
+			assert.Equal(t, fmt.Sprint(expected_err), fmt.Sprint(err), input)
+
+			expected_diagnostics := []string{}
+
+			for _, d := range parser.Diagnostics() {
+				expected_diagnostics = append(expected_diagnostics, fmt.Sprintf("%d:%d %s %s", d.Location.Line, d.Location.Column, d.Severity, d.Message))
+			}
+
+			sort.Strings(expected_diagnostics)
+			assert.Equal(t, expected_diagnostics, diagnostics, input)
+
+			if expected_err == nil {
+				if expected != nil {
+					expected.Comments = nil
+				}
+
+				expected_json, _ := json.Marshal(expected)
+				actual_json, _ := json.Marshal(actual)
+
+				assert.Equal(t, string(expected_json), string(actual_json), input)
+			}
+		}
+	}
+}
+
+func Test_AStreamReportsTagsAndGoalsInOrder(t *testing.T) {
+
+	events := make([]string, 0)
+
+	err := Stream(bytes.NewBufferString("@a\nActor: A\n    Blurb\n    @b\n    Goal: One\n    Goals:\n        Two\n"), StreamHandler{
+		OnActor: func(a *Actor) error {
+			events = append(events, "actor "+a.Name)
+			return nil
+		},
+		OnTag: func(tag *gherkin.Tag) error {
+			events = append(events, "tag "+tag.Name)
+			return nil
+		},
+		OnGoal: func(goal *Goal) error {
+			events = append(events, "goal "+goal.Name)
+			return nil
+		},
+		OnBlurb: func(text string, location *gherkin.Location) error {
+			events = append(events, fmt.Sprintf("blurb %s at %d:%d", text, location.Line, location.Column))
+			return nil
+		},
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"tag a", "actor A", "blurb Blurb at 3:5", "tag b", "goal One", "goal Two"}, events)
+}
+
+func Test_AStreamStopsWhenAHandlerFails(t *testing.T) {
+
+	stop := fmt.Errorf("Stop")
+	goals := 0
+
+	err := Stream(bytes.NewBufferString("Actor: A\n    Goals:\n        One\n        Two\n"), StreamHandler{
+		OnGoal: func(goal *Goal) error {
+			goals++
+			return stop
+		},
+	})
+
+	assert.Equal(t, stop, err)
+	assert.Equal(t, 1, goals)
+}
+
+// goalReader writes the lines of an actor with many goals as they are read
+type goalReader struct {
+	goals, read int
+	buf         bytes.Buffer
+}
+
+func (r *goalReader) Read(p []byte) (int, error) {
+
+	if r.buf.Len() == 0 {
+
+		if r.read > r.goals {
+			return 0, io.EOF
+		}
+
+		if r.read == 0 {
+			r.buf.WriteString("Actor: Generated\n    Goals:\n")
+		} else {
+			fmt.Fprintf(&r.buf, "        Goal %d\n", r.read)
+		}
+
+		r.read++
+	}
+
+	return r.buf.Read(p)
+}
+
+func Test_AStreamReportsGoalsBeforeTheEndOfTheInput(t *testing.T) {
+
+	reader := &goalReader{goals: 10000}
+	goals := 0
+
+	err := Stream(reader, StreamHandler{
+		OnGoal: func(goal *Goal) error {
+			goals++
+			assert.True(t, reader.read < reader.goals || goals > reader.goals-10, "Goal %d is reported late", goals)
+			assert.True(t, strings.HasPrefix(goal.Name, "Goal "))
+			return nil
+		},
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 10000, goals)
+}
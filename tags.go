@@ -0,0 +1,221 @@
+package actor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// DefaultTagRegistryFile is the tag registry that LoadProject looks for in
+// the project's root
+const DefaultTagRegistryFile = ".actor-tags.json"
+
+type TagScope string
+
+const (
+	TagScopeActor TagScope = "actor"
+	TagScopeGoal  TagScope = "goal"
+)
+
+// TagDefinition registers a tag. A tag without a scope may be used on both
+// actors and goals.
+type TagDefinition struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Scope       []TagScope `json:"scope,omitempty"`
+	Deprecated  bool       `json:"deprecated,omitempty"`
+	ReplacedBy  string     `json:"replacedBy,omitempty"`
+}
+
+// TagRegistry is the list of tags that may be used, as read from a file like:
+//
+//	{
+//	    "tags": [
+//	        {"name": "admin", "description": "Has full access", "scope": ["actor"]},
+//	        {"name": "wip", "deprecated": true, "replacedBy": "draft"},
+//	        {"name": "draft"}
+//	    ]
+//	}
+//
+// Set as ParserOptions.Tags, the parser rejects tags that aren't registered
+// or are used out of scope, and warns about deprecated ones.
+type TagRegistry struct {
+	Tags []*TagDefinition `json:"tags"`
+
+	// Tags by name, made from Tags on the first Lookup if the registry
+	// wasn't made by NewTagRegistry
+	byName map[string]*TagDefinition
+	once   sync.Once
+}
+
+func NewTagRegistry(tags ...*TagDefinition) (*TagRegistry, error) {
+
+	r := &TagRegistry{Tags: tags}
+	r.once.Do(func() { r.byName = make(map[string]*TagDefinition) })
+
+	for _, tag := range tags {
+
+		name, err := validateTagName(tag.Name)
+
+		if err != nil {
+			return nil, err
+		}
+
+		tag.Name = name
+
+		if _, ok := r.byName[tag.Name]; ok {
+			return nil, fmt.Errorf("Tag '%s' is registered more than once", tag.Name)
+		}
+
+		for _, scope := range tag.Scope {
+			if scope != TagScopeActor && scope != TagScopeGoal {
+				return nil, fmt.Errorf("Tag '%s' has unknown scope '%s'", tag.Name, scope)
+			}
+		}
+
+		r.byName[tag.Name] = tag
+	}
+
+	for _, tag := range tags {
+
+		if tag.ReplacedBy == "" {
+			continue
+		}
+
+		tag.ReplacedBy = strings.TrimPrefix(tag.ReplacedBy, "@")
+
+		if _, ok := r.byName[tag.ReplacedBy]; !ok {
+			return nil, fmt.Errorf("Tag '%s' is replaced by '%s', which isn't registered", tag.Name, tag.ReplacedBy)
+		}
+	}
+
+	return r, nil
+}
+
+func ReadTagRegistry(reader io.Reader) (*TagRegistry, error) {
+
+	file := TagRegistry{}
+	decoder := json.NewDecoder(reader)
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("Invalid tag registry: %s", err)
+	}
+
+	return NewTagRegistry(file.Tags...)
+}
+
+func ReadTagRegistryFile(path string) (*TagRegistry, error) {
+
+	file, err := os.Open(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	registry, err := ReadTagRegistry(file)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err)
+	}
+
+	return registry, nil
+}
+
+// Lookup returns the definition of a tag, or nil if it isn't registered
+func (r *TagRegistry) Lookup(name string) *TagDefinition {
+
+	r.once.Do(func() {
+		r.byName = make(map[string]*TagDefinition)
+
+		for _, tag := range r.Tags {
+			r.byName[strings.TrimPrefix(tag.Name, "@")] = tag
+		}
+	})
+
+	return r.byName[name]
+}
+
+// Allows reports whether a registered tag may be used in a scope
+func (d *TagDefinition) Allows(scope TagScope) bool {
+
+	if len(d.Scope) == 0 {
+		return true
+	}
+
+	for _, s := range d.Scope {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Suggest returns the registered tag closest to a name, as a correction for
+// a typo, or "" if none is close
+func (r *TagRegistry) Suggest(name string) string {
+
+	best, bestDistance := "", 0
+
+	// Allow about one mistake for every three characters
+	limit := utf8.RuneCountInString(name)/3 + 1
+
+	for _, tag := range r.Tags {
+
+		distance := levenshtein(strings.ToLower(name), strings.ToLower(tag.Name))
+
+		if distance <= limit && (best == "" || distance < bestDistance) {
+			best, bestDistance = tag.Name, distance
+		}
+	}
+
+	return best
+}
+
+// levenshtein is the number of single character insertions, deletions and
+// substitutions that turn one string into the other
+func levenshtein(a, b string) int {
+
+	s, t := []rune(a), []rune(b)
+	previous := make([]int, len(t)+1)
+	current := make([]int, len(t)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(s); i++ {
+
+		current[0] = i
+
+		for j := 1; j <= len(t); j++ {
+
+			cost := 1
+
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+
+			current[j] = previous[j-1] + cost
+
+			if previous[j]+1 < current[j] {
+				current[j] = previous[j] + 1
+			}
+
+			if current[j-1]+1 < current[j] {
+				current[j] = current[j-1] + 1
+			}
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(t)]
+}
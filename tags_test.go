@@ -0,0 +1,78 @@
+package actor
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ItReadsATagRegistry(t *testing.T) {
+
+	registry, err := ReadTagRegistry(bytes.NewBufferString(`{
+		"tags": [
+			{"name": "@admin", "description": "Has full access", "scope": ["actor"]},
+			{"name": "wip", "deprecated": true, "replacedBy": "@draft"},
+			{"name": "draft"}
+		]
+	}`))
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(registry.Tags))
+	assert.Equal(t, &TagDefinition{Name: "admin", Description: "Has full access", Scope: []TagScope{TagScopeActor}}, registry.Lookup("admin"))
+	assert.True(t, registry.Lookup("admin").Allows(TagScopeActor))
+	assert.False(t, registry.Lookup("admin").Allows(TagScopeGoal))
+	assert.True(t, registry.Lookup("wip").Allows(TagScopeGoal))
+	assert.Equal(t, "draft", registry.Lookup("wip").ReplacedBy)
+	assert.Nil(t, registry.Lookup("final"))
+}
+
+func Test_ItLooksUpTagsInARegistryItDidntMake(t *testing.T) {
+
+	registry := &TagRegistry{Tags: []*TagDefinition{{Name: "admin"}, {Name: "@wip"}}}
+
+	assert.Equal(t, "admin", registry.Lookup("admin").Name)
+	assert.NotNil(t, registry.Lookup("wip"))
+	assert.Nil(t, registry.Lookup("draft"))
+}
+
+func Test_ItRejectsInvalidTagRegistries(t *testing.T) {
+
+	for _, test := range []struct {
+		registry string
+		expected string
+	}{
+		{`{"tags": [{"name": "not valid"}]}`, "Tag 'not valid' is not a valid tag name"},
+		{`{"tags": [{"name": "a"}, {"name": "@a"}]}`, "Tag 'a' is registered more than once"},
+		{`{"tags": [{"name": "a", "scope": ["feature"]}]}`, "Tag 'a' has unknown scope 'feature'"},
+		{`{"tags": [{"name": "a", "colour": "red"}]}`, `Invalid tag registry: json: unknown field "colour"`},
+		{`{"tags": [{"name": "wip", "replacedBy": "draf"}, {"name": "draft"}]}`, "Tag 'wip' is replaced by 'draf', which isn't registered"},
+	} {
+		_, err := ReadTagRegistry(bytes.NewBufferString(test.registry))
+		assert.Equal(t, test.expected, err.Error())
+	}
+}
+
+func Test_ItSuggestsCloseTags(t *testing.T) {
+
+	registry, err := NewTagRegistry(&TagDefinition{Name: "admin"}, &TagDefinition{Name: "administrator"}, &TagDefinition{Name: "ux"})
+	assert.Nil(t, err)
+
+	for name, expected := range map[string]string{
+		"admn":         "admin",
+		"Admin":        "admin",
+		"adminstrator": "administrator",
+		"ui":           "ux",
+		"guest":        "",
+		"u":            "ux",
+	} {
+		assert.Equal(t, expected, registry.Suggest(name), name)
+	}
+}
+
+func Test_Levenshtein(t *testing.T) {
+	assert.Equal(t, 0, levenshtein("", ""))
+	assert.Equal(t, 3, levenshtein("", "abc"))
+	assert.Equal(t, 3, levenshtein("kitten", "sitting"))
+	assert.Equal(t, 1, levenshtein("café", "cafe"))
+}
@@ -4,14 +4,14 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"unicode/utf8"
 )
 
 //go:generate stringer -type=tokenKind
 type tokenKind int
 
-var commentMatcher = regexp.MustCompile(`#.+$`)
 var tagMatcher = regexp.MustCompile(`^@([a-zA-Z][a-zA-Z0-9_-]*)$`)
-var keywordMatcher = regexp.MustCompile(`^(.+):\s?(.+)?$`)
+var languageMatcher = regexp.MustCompile(`^\s*#\s*language\s*:\s*([a-zA-Z_-]+)\s*$`)
 
 const (
 	token_comment tokenKind = iota
@@ -20,29 +20,91 @@ const (
 	token_text
 	token_goals
 	token_goal
+	token_language
+	token_docString
+	token_tableRow
+	token_tableCell
+	token_unknownKeyword
+	token_aliases
 )
 
-var tokenKindsByString = map[string]tokenKind{
-	"actor": token_actorDefinition,
-	"goals": token_goals,
-	"goal":  token_goal,
-}
+var docStringDelimiters = []string{`"""`, "```"}
 
 type token struct {
 	kind    tokenKind
 	content string
+	column  int    // Offset in characters of the content within the line
+	keyword string // The unrecognised keyword of a token_unknownKeyword
 }
 
-type tokeniser struct{}
+type tokeniser struct {
+	dialect *dialect
+	lenient bool
+}
 
 func newTokeniser() *tokeniser {
-	return &tokeniser{}
+	return &tokeniser{dialect: dialects[defaultLanguage]}
 }
 
-func (t *tokeniser) tokenise(l *line) (tokens []token, err error) {
+func (t *tokeniser) setLanguage(language string) error {
+
+	d, err := dialectFor(language)
+
+	if err != nil {
+		return err
+	}
+
+	t.dialect = d
+
+	return nil
+}
+
+// tokenise returns the tokens of a line, which are kept on the line so that
+// a subtree reused from an earlier lex isn't tokenised again
+func (t *tokeniser) tokenise(l *line) ([]token, error) {
+
+	if l.tokenisedBy.dialect != nil && l.tokenisedBy == *t {
+		return l.tokens, nil
+	}
+
+	tokens, err := t.tokeniseLine(l)
+
+	if err == nil {
+		l.tokens, l.tokenisedBy = tokens, *t
+	}
+
+	return tokens, err
+}
+
+func (t *tokeniser) tokeniseLine(l *line) (tokens []token, err error) {
+
+	// A language header is a special comment
+	if matches := languageMatcher.FindStringSubmatch(string(l.content)); matches != nil {
+		return []token{
+			token{kind: token_language, content: matches[1]},
+		}, nil
+	}
+
+	// Doc strings carry an optional media type, and no comments
+	if delimiter := l.content.docStringDelimiter(); delimiter != "" {
+		return []token{
+			token{kind: token_docString, content: strings.Trim(string(l.content), " \t")[len(delimiter):]},
+		}, nil
+	}
+
+	// Table rows may contain anything, including #
+	if strings.HasPrefix(string(l.content), "|") {
+		return t.tokeniseTableRow(l.content)
+	}
 
 	// Remove comments
-	content := strings.Trim(string(commentMatcher.ReplaceAll([]byte(l.content), []byte(""))), " \t")
+	content := string(l.content)
+
+	if i := indexUnescaped(content, '#'); i >= 0 {
+		content = content[:i]
+	}
+
+	content = strings.Trim(content, " \t")
 
 	// Check for empty
 	if content == "" {
@@ -54,41 +116,126 @@ func (t *tokeniser) tokenise(l *line) (tokens []token, err error) {
 		return t.tokeniseTags(lineContent(content))
 	}
 
-	// Check for Something:
-	if keywordMatcher.Match([]byte(content)) {
-		return t.tokeniseKeyword(lineContent(content))
+	// Check for Something:, where Something is a single word
+	if i := indexUnescaped(content, ':'); i > 0 && !strings.ContainsAny(content[:i], " \t") {
+		return t.tokeniseKeyword(lineContent(content), i)
 	}
 
 	// Assume the result is text
 	return []token{
-		token{kind: token_text, content: content},
+		token{kind: token_text, content: unescape(content)},
 	}, nil
 }
 
 func (t *tokeniser) tokeniseTags(content lineContent) (tokens []token, err error) {
-	tags := strings.Fields(string(content))
 
-	for i, v := range tags {
+	offset := 0
+
+	for i, v := range strings.Fields(string(content)) {
+
+		offset += strings.Index(string(content[offset:]), v)
+
 		if matched := tagMatcher.Find([]byte(v)); matched != nil {
-			tokens = append(tokens, token{kind: token_tag, content: string(matched[1:])})
+			tokens = append(tokens, token{kind: token_tag, content: string(matched[1:]), column: characters(content, offset)})
 		} else {
 			return nil, fmt.Errorf("Tag '%s' (#%d on the line) is not valid", v, i+1)
 		}
+
+		offset += len(v)
 	}
 
 	return
 }
 
-func (t *tokeniser) tokeniseKeyword(content lineContent) (tokens []token, err error) {
+// characters converts a byte offset within the content to a character offset
+func characters(content lineContent, offset int) int {
+	return utf8.RuneCountInString(string(content[:offset]))
+}
+
+func (t *tokeniser) tokeniseKeyword(content lineContent, colon int) (tokens []token, err error) {
 
-	terms := keywordMatcher.FindStringSubmatch(string(content))
-	typ, ok := tokenKindsByString[strings.ToLower(terms[1])]
+	keyword := unescape(string(content[:colon]))
+	typ, ok := t.dialect.kind(keyword)
+
+	if !ok && t.lenient {
+		return []token{
+			token{kind: token_unknownKeyword, content: unescape(string(content)), keyword: keyword},
+		}, nil
+	}
 
 	if !ok {
-		return nil, fmt.Errorf("Unrecognised keyword '%s'", terms[1])
+		return nil, fmt.Errorf("Unrecognised keyword '%s'", keyword)
 	}
 
-	tokens = append(tokens, token{kind: typ, content: terms[2]})
+	value := strings.TrimLeft(string(content[colon+1:]), " \t")
+
+	tokens = append(tokens, token{
+		kind:    typ,
+		content: unescape(strings.TrimRight(value, " \t")),
+		column:  characters(content, len(content)-len(value)),
+	})
+
+	return
+}
+
+func (t *tokeniser) tokeniseTableRow(content lineContent) (tokens []token, err error) {
+
+	tokens = append(tokens, token{kind: token_tableRow, content: string(content)})
+
+	cell := make([]rune, 0)
+	open := false
+	escaped := false
+	column := 0
+
+	// Remember where the value starts for the cell's location
+	add := func(offset int, r ...rune) {
+		if strings.TrimSpace(string(cell)) == "" && strings.TrimSpace(string(r)) != "" {
+			column = offset
+		}
+
+		cell = append(cell, r...)
+	}
+
+	for i, r := range string(content) {
+
+		switch {
+		case escaped:
+			switch r {
+			case 'n':
+				add(i-1, '\n')
+			case '|', '\\':
+				add(i-1, r)
+			default:
+				add(i-1, '\\', r)
+			}
+
+			escaped = false
+
+		case r == '\\':
+			escaped = true
+
+		case r == '|':
+			if open {
+				tokens = append(tokens, token{
+					kind:    token_tableCell,
+					content: strings.Trim(string(cell), " \t"),
+					column:  characters(content, column),
+				})
+			}
+
+			cell = cell[:0]
+			column = i + 1
+			open = true
+
+		default:
+			add(i, r)
+		}
+	}
+
+	// Only a comment may follow the last cell
+	if rest := strings.TrimSpace(string(cell)); escaped || (rest != "" && rest[0] != '#') {
+		return nil, fmt.Errorf("Table row must end with '|'")
+	}
 
 	return
 }
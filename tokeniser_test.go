@@ -23,7 +23,15 @@ func Test_StringsTokeniseAsExpected(t *testing.T) {
 			line: "@tag1 @tag2",
 			tokens: []token{
 				{kind: token_tag, content: "tag1"},
-				{kind: token_tag, content: "tag2"},
+				{kind: token_tag, content: "tag2", column: 6},
+			},
+		},
+		{
+			// Tags further apart
+			line: "@tag1  @tag2",
+			tokens: []token{
+				{kind: token_tag, content: "tag1"},
+				{kind: token_tag, content: "tag2", column: 7},
 			},
 		},
 		{
@@ -54,6 +62,16 @@ func Test_StringsTokeniseAsExpected(t *testing.T) {
 			err:  fmt.Errorf("Tag '@tag*1' (#1 on the line) is not valid"),
 		},
 
+		{
+			// Columns count characters, not bytes
+			line: "| été | x |",
+			tokens: []token{
+				{kind: token_tableRow, content: "| été | x |"},
+				{kind: token_tableCell, content: "été", column: 2},
+				{kind: token_tableCell, content: "x", column: 8},
+			},
+		},
+
 		///////////////////////////////
 		// Keywords
 		///////////////////////////////
@@ -61,19 +79,19 @@ func Test_StringsTokeniseAsExpected(t *testing.T) {
 		{
 			line: "Actor:",
 			tokens: []token{
-				{kind: token_actorDefinition, content: ""},
+				{kind: token_actorDefinition, content: "", column: 6},
 			},
 		},
 		{
 			line: "Actor: #this is a comment",
 			tokens: []token{
-				{kind: token_actorDefinition, content: ""},
+				{kind: token_actorDefinition, content: "", column: 6},
 			},
 		},
 		{
 			line: "Actor: Some actor",
 			tokens: []token{
-				{kind: token_actorDefinition, content: "Some actor"},
+				{kind: token_actorDefinition, content: "Some actor", column: 7},
 			},
 		},
 
@@ -100,6 +118,105 @@ func Test_StringsTokeniseAsExpected(t *testing.T) {
 			},
 		},
 
+		///////////////////////////////
+		// Doc strings
+		///////////////////////////////
+
+		{
+			line: `"""`,
+			tokens: []token{
+				{kind: token_docString, content: ""},
+			},
+		},
+
+		{
+			line: "```markdown # not a comment",
+			tokens: []token{
+				{kind: token_docString, content: "markdown # not a comment"},
+			},
+		},
+
+		///////////////////////////////
+		// Table rows
+		///////////////////////////////
+
+		{
+			line: "| Device | #uses | a \\| b |",
+			tokens: []token{
+				{kind: token_tableRow, content: "| Device | #uses | a \\| b |"},
+				{kind: token_tableCell, content: "Device", column: 2},
+				{kind: token_tableCell, content: "#uses", column: 11},
+				{kind: token_tableCell, content: "a | b", column: 19},
+			},
+		},
+
+		{
+			line: "|| x\\n\\\\ | # comment",
+			tokens: []token{
+				{kind: token_tableRow, content: "|| x\\n\\\\ | # comment"},
+				{kind: token_tableCell, content: "", column: 1},
+				{kind: token_tableCell, content: "x\n\\", column: 3},
+			},
+		},
+
+		{
+			line: "| a | b",
+			err:  fmt.Errorf("Table row must end with '|'"),
+		},
+
+		///////////////////////////////
+		// Language header
+		///////////////////////////////
+
+		{
+			line: "# language: fr",
+			tokens: []token{
+				{kind: token_language, content: "fr"},
+			},
+		},
+
+		{
+			line: "#language:pt",
+			tokens: []token{
+				{kind: token_language, content: "pt"},
+			},
+		},
+
+		{
+			line: "Reach \\#1 in search # but not this",
+			tokens: []token{
+				{kind: token_text, content: "Reach #1 in search"},
+			},
+		},
+
+		{
+			line: "Some text: with a colon",
+			tokens: []token{
+				{kind: token_text, content: "Some text: with a colon"},
+			},
+		},
+
+		{
+			line: "Note\\: escaped colon",
+			tokens: []token{
+				{kind: token_text, content: "Note: escaped colon"},
+			},
+		},
+
+		{
+			line: "\\@not-a-tag",
+			tokens: []token{
+				{kind: token_text, content: "@not-a-tag"},
+			},
+		},
+
+		{
+			line: "Goal: Reach \\#1: in search",
+			tokens: []token{
+				{kind: token_goal, content: "Reach #1: in search", column: 6},
+			},
+		},
+
 		///////////////////////////////
 		// Empty
 		///////////////////////////////
@@ -124,3 +241,19 @@ func Test_StringsTokeniseAsExpected(t *testing.T) {
 		assert.Equal(t, input.tokens, tokens)
 	}
 }
+
+func Test_KeywordsTokeniseInTheSelectedDialect(t *testing.T) {
+
+	tkn := newTokeniser()
+	assert.Nil(t, tkn.setLanguage("fr"))
+
+	tokens, err := tkn.tokenise(&line{content: "Acteur: Un acteur"})
+	assert.Nil(t, err)
+	assert.Equal(t, []token{{kind: token_actorDefinition, content: "Un acteur", column: 8}}, tokens)
+
+	tokens, err = tkn.tokenise(&line{content: "Actor: An actor"})
+	assert.Equal(t, fmt.Errorf("Unrecognised keyword 'Actor'"), err)
+	assert.Nil(t, tokens)
+
+	assert.Equal(t, fmt.Errorf("Unsupported language 'xx'"), tkn.setLanguage("xx"))
+}
@@ -4,9 +4,9 @@ package actor
 
 import "fmt"
 
-const _tokenKind_name = "token_commenttoken_tagtoken_actorDefinitiontoken_texttoken_goalstoken_goaltoken_empty"
+const _tokenKind_name = "token_commenttoken_tagtoken_actorDefinitiontoken_texttoken_goalstoken_goaltoken_languagetoken_docStringtoken_tableRowtoken_tableCelltoken_unknownKeywordtoken_aliases"
 
-var _tokenKind_index = [...]uint8{0, 13, 22, 43, 53, 64, 74, 85}
+var _tokenKind_index = [...]uint8{0, 13, 22, 43, 53, 64, 74, 88, 103, 117, 132, 152, 165}
 
 func (i tokenKind) String() string {
 	if i < 0 || i >= tokenKind(len(_tokenKind_index)-1) {
@@ -0,0 +1,112 @@
+package actor
+
+import (
+	"fmt"
+
+	gherkin "github.com/cucumber/gherkin-go"
+)
+
+// Node is a part of the actor model that Walk visits: *Actor, *gherkin.Tag,
+// *Alias, BlurbLine, *DocString, *DataTable, *TableRow, *TableCell, *Goal or
+// *Comment
+type Node interface{}
+
+// BlurbLine is a line of an actor's blurb
+type BlurbLine string
+
+// A Visitor's Visit method is called with each node Walk finds. If it returns
+// a visitor w, the node's children are walked with w, followed by a call of
+// w.Visit(nil).
+type Visitor interface {
+	Visit(node Node) (w Visitor)
+}
+
+// Walk visits a node and then, depending on the visitor, its children in the
+// order they're written: an actor's tags, aliases, blurb, doc string, data
+// table, goals and comments, and a goal's tags, doc string and data table.
+func Walk(v Visitor, node Node) {
+
+	if v = v.Visit(node); v == nil {
+		return
+	}
+
+	switch n := node.(type) {
+
+	case *Actor:
+		walkTags(v, n.Tags)
+
+		for _, alias := range n.Aliases {
+			Walk(v, alias)
+		}
+
+		for _, blurb := range n.Blurb {
+			Walk(v, BlurbLine(blurb))
+		}
+
+		walkContent(v, n.DocString, n.DataTable)
+
+		for _, goal := range n.Goals {
+			Walk(v, goal)
+		}
+
+		for _, comment := range n.Comments {
+			Walk(v, comment)
+		}
+
+	case *Goal:
+		walkTags(v, n.Tags)
+		walkContent(v, n.DocString, n.DataTable)
+
+	case *DataTable:
+		for _, row := range n.Rows {
+			Walk(v, row)
+		}
+
+	case *TableRow:
+		for _, cell := range n.Cells {
+			Walk(v, cell)
+		}
+
+	case *gherkin.Tag, *Alias, BlurbLine, *DocString, *TableCell, *Comment:
+		// No children
+
+	default:
+		panic(fmt.Sprintf("actor.Walk: unexpected node type %T", n))
+	}
+
+	v.Visit(nil)
+}
+
+func walkTags(v Visitor, tags []*gherkin.Tag) {
+	for _, tag := range tags {
+		Walk(v, tag)
+	}
+}
+
+func walkContent(v Visitor, docString *DocString, dataTable *DataTable) {
+
+	if docString != nil {
+		Walk(v, docString)
+	}
+
+	if dataTable != nil {
+		Walk(v, dataTable)
+	}
+}
+
+type inspector func(Node) bool
+
+func (f inspector) Visit(node Node) Visitor {
+
+	if f(node) {
+		return f
+	}
+
+	return nil
+}
+
+// Inspect walks a node, calling f with each node and, after its children,
+// with nil. The children of a node are skipped when f returns false.
+func Inspect(node Node, f func(Node) bool) {
+	Walk(inspector(f), node)
+}
@@ -0,0 +1,125 @@
+package actor
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	gherkin "github.com/cucumber/gherkin-go"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// describe names a node for the tests
+func describe(node Node) string {
+
+	switch n := node.(type) {
+	case nil:
+		return "end"
+	case *Actor:
+		return "actor " + n.Name
+	case *gherkin.Tag:
+		return "tag " + n.Name
+	case *Alias:
+		return "alias " + n.Name
+	case BlurbLine:
+		return "blurb " + string(n)
+	case *DocString:
+		return "doc string " + n.Content
+	case *DataTable:
+		return "table"
+	case *TableRow:
+		return "row"
+	case *TableCell:
+		return "cell " + n.Value
+	case *Goal:
+		return "goal " + n.Name
+	case *Comment:
+		return "comment " + n.Text
+	}
+
+	return fmt.Sprintf("%T", node)
+}
+
+func Test_InspectVisitsEveryPartOfAnActorInOrder(t *testing.T) {
+
+	a, err := NewParser(bytes.NewBufferString(`@a
+Actor: Someone
+    Aliases:
+        Anyone
+    Blurb # note
+    """
+    Doc
+    """
+    | x |
+
+    @b
+    Goal: First
+        | y |
+`)).Parse()
+
+	assert.Nil(t, err)
+
+	visited := make([]string, 0)
+
+	Inspect(a, func(node Node) bool {
+		visited = append(visited, describe(node))
+		return true
+	})
+
+	assert.Equal(t, []string{
+		"actor Someone",
+		"tag a", "end",
+		"alias Anyone", "end",
+		"blurb Blurb", "end",
+		"doc string Doc", "end",
+		"table", "row", "cell x", "end", "end", "end",
+		"goal First",
+		"tag b", "end",
+		"table", "row", "cell y", "end", "end", "end",
+		"end",
+		"comment # note", "end",
+		"end",
+	}, visited)
+}
+
+func Test_InspectSkipsTheChildrenOfANode(t *testing.T) {
+
+	visited := make([]string, 0)
+
+	Inspect(newMockActor(), func(node Node) bool {
+
+		if node != nil {
+			visited = append(visited, describe(node))
+		}
+
+		_, goal := node.(*Goal)
+
+		return !goal
+	})
+
+	assert.Equal(t, []string{"actor Mock actor", "tag tag1", "tag tag2", "blurb Blurb line 1", "blurb BLurb line 2", "goal Goal 1", "goal Goal 2", "goal Goal 3"}, visited)
+}
+
+type goalCounter struct {
+	goals int
+}
+
+func (c *goalCounter) Visit(node Node) Visitor {
+
+	if _, ok := node.(*Goal); ok {
+		c.goals++
+		return nil
+	}
+
+	return c
+}
+
+func Test_WalkTakesAVisitor(t *testing.T) {
+
+	counter := &goalCounter{}
+	Walk(counter, newMockActor())
+
+	assert.Equal(t, 3, counter.goals)
+	assert.Panics(t, func() { Walk(counter, "not a node") })
+}
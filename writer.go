@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"unicode/utf8"
 
 	gherkin "github.com/cucumber/gherkin-go"
 )
@@ -11,10 +12,28 @@ import (
 type writer struct {
 	writer      io.Writer
 	indentation int
+	dialect     *dialect
 }
 
 func newWriter(w io.Writer) *writer {
-	return &writer{writer: w}
+	return &writer{writer: w, dialect: dialects[defaultLanguage]}
+}
+
+func (w *writer) setLanguage(language string) error {
+
+	d, err := dialectFor(language)
+
+	if err != nil {
+		return err
+	}
+
+	w.dialect = d
+
+	return nil
+}
+
+func (w *writer) keyword(kind tokenKind) string {
+	return w.dialect.keyword(kind)
 }
 
 func (w *writer) indent() {
@@ -69,21 +88,141 @@ func (w *writer) writeTags(tags []*gherkin.Tag) error {
 
 func (w *writer) writeKeyword(keyword, value string) error {
 
-	keywordString := fmt.Sprintf("%s%s: %s", w.indentString(), keyword, value)
+	keywordString := fmt.Sprintf("%s%s: %s", w.indentString(), keyword, escapeValue(value))
 
 	return w.writeLine([]byte(strings.TrimRight(keywordString, " ")))
 }
 
 func (w *writer) writeBlurb(value string) error {
 
-	blurbString := fmt.Sprintf("%s%s", w.indentString(), value)
+	blurbString := fmt.Sprintf("%s%s", w.indentString(), escapeText(value))
 
 	return w.writeLine([]byte(blurbString))
 }
 
+func (w *writer) writeDocString(d *DocString) error {
+
+	delimiter := d.Delimiter
+
+	if delimiter == "" {
+		delimiter = docStringDelimiters[0]
+	}
+
+	escaped := strings.Repeat(`\`+delimiter[:1], len(delimiter))
+
+	if err := w.writeLine([]byte(w.indentString() + delimiter + d.ContentType)); err != nil {
+		return err
+	}
+
+	for _, content := range strings.Split(strings.Replace(d.Content, delimiter, escaped, -1), "\n") {
+
+		if content == "" {
+			if err := w.newLine(); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if err := w.writeLine([]byte(w.indentString() + content)); err != nil {
+			return err
+		}
+	}
+
+	return w.writeLine([]byte(w.indentString() + delimiter))
+}
+
+func (w *writer) writeTable(t *DataTable) error {
+
+	widths := make([]int, 0)
+
+	// Columns are aligned on their widest cell
+	for _, row := range t.Rows {
+		for i, cell := range row.Cells {
+
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+
+			if width := utf8.RuneCountInString(escapeTableCell(cell.Value)); width > widths[i] {
+				widths[i] = width
+			}
+		}
+	}
+
+	for _, row := range t.Rows {
+
+		rowString := w.indentString() + "|"
+
+		for i, cell := range row.Cells {
+			value := escapeTableCell(cell.Value)
+			rowString += " " + value + strings.Repeat(" ", widths[i]-utf8.RuneCountInString(value)) + " |"
+		}
+
+		if err := w.writeLine([]byte(rowString)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func escapeTableCell(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "|", `\|`, "\n", `\n`).Replace(value)
+}
+
+func (w *writer) writeLanguage() error {
+
+	if w.dialect.language == defaultLanguage {
+		return nil
+	}
+
+	return w.writeComment(fmt.Sprintf("language: %s", w.dialect.language))
+}
+
 func (w *writer) writeComment(value string) error {
 
 	commentString := fmt.Sprintf("%s# %s", w.indentString(), value)
 
 	return w.writeLine([]byte(commentString))
 }
+
+// writeComments writes comments as they were found, one to a line
+func (w *writer) writeComments(comments []*Comment) error {
+
+	for _, comment := range comments {
+		if err := w.writeLine([]byte(comment.Text)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeConflict writes both sides of a merge conflict between git's conflict
+// markers, which start at the beginning of the line whatever the indentation
+func (w *writer) writeConflict(c *Conflict) error {
+
+	sides := []struct {
+		marker string
+		write  func(w *writer) error
+	}{
+		{"<<<<<<< ours", c.ours},
+		{"=======", c.theirs},
+	}
+
+	for _, side := range sides {
+
+		if err := w.writeLine([]byte(side.marker)); err != nil {
+			return err
+		}
+
+		if side.write != nil {
+			if err := side.write(w); err != nil {
+				return err
+			}
+		}
+	}
+
+	return w.writeLine([]byte(">>>>>>> theirs"))
+}
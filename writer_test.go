@@ -136,3 +136,20 @@ func Test_AWriterCanWriteComments(t *testing.T) {
 		assert.Equal(t, input.output+"\n", buf.String())
 	}
 }
+
+func Test_AWriterAlignsTableColumns(t *testing.T) {
+
+	buf := &bytes.Buffer{}
+	w := newWriter(buf)
+	w.setIndentation(1)
+
+	table := &DataTable{
+		Rows: []*TableRow{
+			{Cells: []*TableCell{{Value: "device"}, {Value: "a|b"}}},
+			{Cells: []*TableCell{{Value: "téléphone"}, {Value: "x"}}},
+		},
+	}
+
+	assert.Nil(t, w.writeTable(table))
+	assert.Equal(t, "    | device    | a\\|b |\n    | téléphone | x    |\n", buf.String())
+}